@@ -0,0 +1,92 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chanx
+
+import (
+	"context"
+	"time"
+)
+
+// Batch 将 in 中的数据分批，每批最多 size 个数据；maxWait 大于 0 时，一批中的第一个数据到达后最多等待 maxWait 就发送该批
+// in 关闭时发送剩余的数据并关闭返回的 channel，ctx 结束时直接关闭返回的 channel；size 小于 1 时按 1 处理
+// Batch groups the values from in into batches of at most size values. If maxWait is positive,
+// a batch is sent at most maxWait after its first value arrives.
+// When in is closed, the remaining values are sent and the returned channel is closed. When ctx is done, the returned channel is closed right away.
+// size less than 1 is treated as 1.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	size = max(size, 1)
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var (
+			batch []T
+			timer *time.Timer
+			// timeout 在当前批次为空或不限制等待时间时为 nil，此时不会被选中
+			timeout <-chan time.Time
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		flush := func() bool {
+			if timer != nil && !timer.Stop() {
+				// 清空已经触发但尚未读取的值，避免影响下一批
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timeout = nil
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) >= size {
+					if !flush() {
+						return
+					}
+					continue
+				}
+				if len(batch) == 1 && maxWait > 0 {
+					if timer == nil {
+						timer = time.NewTimer(maxWait)
+					} else {
+						timer.Reset(maxWait)
+					}
+					timeout = timer.C
+				}
+			case <-timeout:
+				timeout = nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
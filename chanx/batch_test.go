@@ -0,0 +1,98 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chanx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []int
+		size int
+		want [][]int
+	}{
+		{
+			name: "没有数据",
+		},
+		{
+			name: "整批",
+			data: []int{1, 2, 3, 4},
+			size: 2,
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "最后一批不满",
+			data: []int{1, 2, 3, 4, 5},
+			size: 2,
+			want: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name: "size 小于 1",
+			data: []int{1, 2},
+			size: 0,
+			want: [][]int{{1}, {2}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGoroutineLeak(t)
+			ctx := context.Background()
+			got, err := ChanToSlice(ctx, Batch(ctx, SliceToChan(ctx, tt.data), tt.size, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBatch_MaxWait(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx := context.Background()
+	in := make(chan int)
+	out := Batch(ctx, in, 10, 20*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	// 未达到 size，等待 maxWait 后发送
+	select {
+	case batch := <-out:
+		assert.Equal(t, []int{1, 2}, batch)
+	case <-time.After(time.Second):
+		t.Fatal("batch not sent after maxWait")
+	}
+
+	in <- 3
+	close(in)
+	got, err := ChanToSlice(ctx, out)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{3}}, got)
+}
+
+func TestBatch_Cancel(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := Batch(ctx, in, 10, time.Hour)
+	in <- 1
+	cancel()
+	for range out {
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chanx provides generic channel plumbing such as fan-in, fan-out, tee and batching.
+//
+// Every function takes a context. Once the context is done, the goroutines started by the function exit
+// and the returned channels are closed, while the input channels may be left partially read.
+//
+// chanx 包提供了扇入、扇出、复制和分批等泛型 channel 工具函数。
+//
+// 所有函数都接收一个 context，context 结束后，函数启动的 goroutine 会退出并关闭返回的 channel，输入的 channel 可能没有被读完。
+package chanx
+
+import (
+	"context"
+)
+
+// OrDone 返回一个转发 in 中数据的 channel，in 关闭或 ctx 结束时关闭
+// OrDone returns a channel that forwards the values from in, and is closed when in is closed or ctx is done.
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	return Buffer(ctx, in, 0)
+}
+
+// Buffer 返回一个容量为 size 的 channel，转发 in 中的数据，使 in 的发送方可以领先接收方最多 size 个数据
+// in 关闭或 ctx 结束时关闭返回的 channel
+// Buffer returns a channel of capacity size that forwards the values from in, which lets the sender of in run ahead of the receiver
+// by up to size values. The returned channel is closed when in is closed or ctx is done.
+func Buffer[T any](ctx context.Context, in <-chan T, size int) <-chan T {
+	out := make(chan T, max(size, 0))
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// SliceToChan 返回一个按顺序发送 data 中元素的 channel，发送完成或 ctx 结束时关闭
+// SliceToChan returns a channel that sends the elements of data in order, and is closed when all of them are sent or ctx is done.
+func SliceToChan[T any](ctx context.Context, data []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range data {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ChanToSlice 读取 in 中的所有数据直到 in 关闭，ctx 先结束时返回已经读取的数据和 ctx.Err()
+// ChanToSlice reads all the values from in until it is closed. If ctx is done first, it returns the values read so far and ctx.Err().
+func ChanToSlice[T any](ctx context.Context, in <-chan T) ([]T, error) {
+	var res []T
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				return res, nil
+			}
+			res = append(res, v)
+		case <-ctx.Done():
+			return res, ctx.Err()
+		}
+	}
+}
+
+// FanOut 启动 n 个 goroutine 竞争地读取 in，并返回各自的输出 channel，每个数据只会被发送到其中一个 channel
+// in 关闭或 ctx 结束时关闭所有输出 channel；n 小于 1 时按 1 处理
+// FanOut starts n goroutines that compete to read from in and returns their output channels,
+// each value is sent to exactly one of them. All the output channels are closed when in is closed or ctx is done.
+// n less than 1 is treated as 1.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]<-chan T, max(n, 1))
+	for i := range outs {
+		outs[i] = OrDone(ctx, in)
+	}
+	return outs
+}
+
+// Tee 返回两个 channel，in 中的每个数据都会被发送到这两个 channel，较慢的接收方会拖慢另一方
+// in 关闭或 ctx 结束时关闭这两个 channel
+// Tee returns two channels and each value from in is sent to both of them, so the slower receiver holds back the other one.
+// Both channels are closed when in is closed or ctx is done.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1 := make(chan T)
+	out2 := make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			// 发送完成的 channel 置为 nil，保证两个 channel 各发送一次
+			o1, o2 := out1, out2
+			for o1 != nil || o2 != nil {
+				select {
+				case o1 <- v:
+					o1 = nil
+				case o2 <- v:
+					o2 = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// recv 从 in 中读取一个数据，in 关闭或 ctx 结束时返回 false
+func recv[T any](ctx context.Context, in <-chan T) (T, bool) {
+	select {
+	case v, ok := <-in:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// send 将 v 发送到 out，ctx 结束时放弃发送并返回 false
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
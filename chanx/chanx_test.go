@@ -0,0 +1,189 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chanx
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceToChan(t *testing.T) {
+	checkGoroutineLeak(t)
+	testCases := []struct {
+		name string
+		data []int
+		want []int
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "多个元素",
+			data: []int{1, 2, 3},
+			want: []int{1, 2, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ChanToSlice(context.Background(), SliceToChan(context.Background(), tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSliceToChan_Cancel(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := SliceToChan(ctx, []int{1, 2, 3})
+	assert.Equal(t, 1, <-ch)
+	cancel()
+	// ctx 结束后 channel 被关闭，最多还能读到一个正在发送的数据
+	got, err := ChanToSlice(context.Background(), ch)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(got), 1)
+}
+
+func TestChanToSlice_Cancel(t *testing.T) {
+	checkGoroutineLeak(t)
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	got, err := ChanToSlice(ctx, in)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, []int{1, 2}, got)
+}
+
+func TestOrDone(t *testing.T) {
+	checkGoroutineLeak(t)
+	got, err := ChanToSlice(context.Background(), OrDone(context.Background(), SliceToChan(context.Background(), []int{1, 2})))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, got)
+
+	// in 永远不关闭时，ctx 结束后关闭
+	never := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := OrDone(ctx, never)
+	cancel()
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func TestBuffer(t *testing.T) {
+	checkGoroutineLeak(t)
+	in := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := Buffer(ctx, in, 2)
+	// 发送方可以领先接收方
+	for i := 0; i < 3; i++ {
+		select {
+		case in <- i:
+		case <-time.After(time.Second):
+			t.Fatal("send blocked")
+		}
+	}
+	close(in)
+	got, err := ChanToSlice(context.Background(), out)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, got)
+}
+
+func TestFanOut(t *testing.T) {
+	checkGoroutineLeak(t)
+	data := make([]int, 100)
+	for i := range data {
+		data[i] = i
+	}
+	outs := FanOut(context.Background(), SliceToChan(context.Background(), data), 4)
+	require.Len(t, outs, 4)
+
+	var (
+		mu  sync.Mutex
+		got []int
+		wg  sync.WaitGroup
+	)
+	for _, out := range outs {
+		wg.Add(1)
+		go func(out <-chan int) {
+			defer wg.Done()
+			for v := range out {
+				mu.Lock()
+				got = append(got, v)
+				mu.Unlock()
+			}
+		}(out)
+	}
+	wg.Wait()
+	sort.Ints(got)
+	assert.Equal(t, data, got)
+
+	assert.Len(t, FanOut(context.Background(), SliceToChan(context.Background(), []int{}), 0), 1)
+}
+
+func TestTee(t *testing.T) {
+	checkGoroutineLeak(t)
+	out1, out2 := Tee(context.Background(), SliceToChan(context.Background(), []int{1, 2, 3}))
+	var got1, got2 []int
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		got1, _ = ChanToSlice(context.Background(), out1)
+	}()
+	go func() {
+		defer wg.Done()
+		got2, _ = ChanToSlice(context.Background(), out2)
+	}()
+	wg.Wait()
+	assert.Equal(t, []int{1, 2, 3}, got1)
+	assert.Equal(t, []int{1, 2, 3}, got2)
+}
+
+func TestTee_Cancel(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out1, out2 := Tee(ctx, SliceToChan(ctx, []int{1, 2, 3}))
+	// 只读取其中一个 channel 时另一个会阻塞，ctx 结束后两个都被关闭
+	assert.Equal(t, 1, <-out1)
+	cancel()
+	for range out1 {
+	}
+	for range out2 {
+	}
+}
+
+// checkGoroutineLeak 检查测试结束后没有残留的 goroutine
+func checkGoroutineLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		// 不使用 assert.Eventually，它会在新的 goroutine 中检查条件
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			if runtime.NumGoroutine() <= before {
+				return
+			}
+		}
+		t.Errorf("goroutine leak: %d before, %d after", before, runtime.NumGoroutine())
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chanx
+
+import (
+	"cmp"
+	"context"
+	"sync"
+)
+
+// Merge 返回一个合并了 chans 中所有数据的 channel，不保证不同 channel 之间数据的顺序，所有 channel 都关闭或 ctx 结束时关闭
+// Merge returns a channel that merges the values from all of chans, the order between different channels is not preserved.
+// It is closed when all of chans are closed or ctx is done.
+func Merge[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, in := range chans {
+		go func(in <-chan T) {
+			defer wg.Done()
+			for {
+				v, ok := recv(ctx, in)
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}(in)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// MergeSorted 将多个升序的 channel 合并为一个升序的 channel，所有 channel 都关闭或 ctx 结束时关闭
+// 每输出一个数据前需要等待每个尚未关闭的 channel 都有数据可读
+// MergeSorted merges channels in ascending order into a channel in ascending order,
+// which is closed when all of chans are closed or ctx is done.
+// Before sending each value, it waits until every channel that is not closed has a value to read.
+func MergeSorted[T cmp.Ordered](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		heads := make([]T, len(chans))
+		// open 记录在 heads 中有数据的 channel 的下标
+		open := make([]int, 0, len(chans))
+		for i, in := range chans {
+			// recv 返回 false 时通过 ctx.Err() 区分 channel 关闭和 ctx 结束
+			if v, ok := recv(ctx, in); ok {
+				heads[i] = v
+				open = append(open, i)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		for len(open) > 0 {
+			minPos := 0
+			for pos, i := range open {
+				if heads[i] < heads[open[minPos]] {
+					minPos = pos
+				}
+			}
+			i := open[minPos]
+			if !send(ctx, out, heads[i]) {
+				return
+			}
+			v, ok := recv(ctx, chans[i])
+			if ctx.Err() != nil {
+				return
+			}
+			if ok {
+				heads[i] = v
+			} else {
+				open = append(open[:minPos], open[minPos+1:]...)
+			}
+		}
+	}()
+	return out
+}
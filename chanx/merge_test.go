@@ -0,0 +1,105 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chanx
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx := context.Background()
+	got, err := ChanToSlice(ctx, Merge(ctx,
+		SliceToChan(ctx, []int{1, 2, 3}),
+		SliceToChan(ctx, []int{4, 5}),
+		SliceToChan(ctx, []int{}),
+	))
+	require.NoError(t, err)
+	sort.Ints(got)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+
+	got, err = ChanToSlice(ctx, Merge[int](ctx))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestMerge_Cancel(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	never := make(chan int)
+	out := Merge(ctx, never, SliceToChan(ctx, []int{1, 2, 3}))
+	assert.Equal(t, 1, <-out)
+	cancel()
+	for range out {
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input [][]int
+		want  []int
+	}{
+		{
+			name: "没有 channel",
+		},
+		{
+			name:  "多个 channel",
+			input: [][]int{{1, 4, 7}, {2, 5, 8}, {3, 6, 9}},
+			want:  []int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			name:  "长度不同且有重复",
+			input: [][]int{{1, 1, 10}, {}, {0, 1, 2, 3, 4}},
+			want:  []int{0, 1, 1, 1, 2, 3, 4, 10},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGoroutineLeak(t)
+			ctx := context.Background()
+			chans := make([]<-chan int, 0, len(tt.input))
+			for _, data := range tt.input {
+				chans = append(chans, SliceToChan(ctx, data))
+			}
+			got, err := ChanToSlice(ctx, MergeSorted(ctx, chans...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMergeSorted_Cancel(t *testing.T) {
+	checkGoroutineLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	// 等待一个永远没有数据的 channel 时，ctx 结束后关闭
+	never := make(chan int)
+	out := MergeSorted(ctx, SliceToChan(ctx, []int{1, 2}), never)
+	cancel()
+	for range out {
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	out = MergeSorted(ctx, SliceToChan(ctx, []int{1, 2, 3}), SliceToChan(ctx, []int{4, 5, 6}))
+	assert.Equal(t, 1, <-out)
+	cancel()
+	for range out {
+	}
+}
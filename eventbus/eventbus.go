@@ -0,0 +1,453 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventbus provides a generic, topic-based in-process publish/subscribe event bus.
+//
+// Topics are dot-separated segments such as "order.created". A subscription pattern may use "*" to match
+// exactly one segment and "#" as the last segment to match zero or more segments.
+//
+// eventbus 包提供了基于主题的泛型进程内发布/订阅事件总线。
+//
+// 主题是由点分隔的若干段，例如 "order.created"。订阅的模式可以使用 "*" 匹配恰好一段，以及使用 "#" 作为最后一段匹配零段或多段。
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/chenmingyong0423/gkit/syncx"
+)
+
+var (
+	// ErrBusClosed 表示事件总线已经关闭
+	// ErrBusClosed is returned when the bus has been closed.
+	ErrBusClosed = errors.New("eventbus: bus is closed")
+	// ErrInvalidTopic 表示主题或订阅的模式不合法
+	// ErrInvalidTopic is returned when a topic or a subscription pattern is invalid.
+	ErrInvalidTopic = errors.New("eventbus: invalid topic")
+	// ErrEventDropped 表示事件因为订阅者的队列已满而被丢弃，通过 WithErrorHandler 设置的回调报告
+	// ErrEventDropped is reported to the callback set by WithErrorHandler when an event is dropped because the queue of a subscriber is full.
+	ErrEventDropped = errors.New("eventbus: event dropped")
+)
+
+// OverflowPolicy 是异步订阅者的队列已满时的处理策略
+// OverflowPolicy decides what to do when the queue of an asynchronous subscriber is full.
+type OverflowPolicy int
+
+const (
+	// DropNewest 丢弃新发布的事件
+	// DropNewest drops the newly published event.
+	DropNewest OverflowPolicy = iota
+	// DropOldest 丢弃队列中最早的事件
+	// DropOldest drops the oldest event in the queue.
+	DropOldest
+	// Block 阻塞发布者，直到队列有空间或发布者的 ctx 结束
+	// Block blocks the publisher until the queue has room or the ctx of the publisher is done.
+	Block
+)
+
+// Option 是 Bus 的可选配置
+// Option configures a Bus.
+type Option func(b *Bus)
+
+// WithErrorHandler 设置报告错误的回调，错误包括订阅者处理事件时发生的 panic（*syncx.PanicError）和被丢弃的事件（ErrEventDropped）
+// WithErrorHandler sets the callback that reports errors,
+// including panics in subscribers (*syncx.PanicError) and dropped events (ErrEventDropped).
+func WithErrorHandler(fn func(topic string, err error)) Option {
+	return func(b *Bus) {
+		b.onError = fn
+	}
+}
+
+// SubscribeOption 是订阅的可选配置
+// SubscribeOption configures a subscription.
+type SubscribeOption func(s *subscriber)
+
+// WithAsync 让订阅者在独立的 goroutine 中按发布顺序处理事件，事件先进入容量为 bufferSize 的队列，队列已满时按 policy 处理
+// 默认情况下，订阅者在发布者的 goroutine 中同步处理事件
+// WithAsync makes the subscriber handle events in order in its own goroutine. Events are queued in a queue of capacity bufferSize,
+// and policy decides what to do when it is full.
+// bufferSize <= 0 时队列没有缓冲，DropNewest 和 DropOldest 在订阅者未就绪时都会丢弃新事件
+// If bufferSize <= 0 the queue is unbuffered, and both DropNewest and DropOldest drop the new event if the subscriber is not ready.
+// By default, subscribers handle events synchronously in the goroutine of the publisher.
+func WithAsync(bufferSize int, policy OverflowPolicy) SubscribeOption {
+	return func(s *subscriber) {
+		s.queue = make(chan envelope, max(bufferSize, 0))
+		s.policy = policy
+	}
+}
+
+// Bus 是基于主题的进程内事件总线
+// Bus is a topic-based in-process event bus.
+type Bus struct {
+	onError func(topic string, err error)
+
+	mu         sync.RWMutex
+	subs       []*subscriber
+	closed     bool
+	publishing sync.WaitGroup
+}
+
+// New 创建一个 Bus
+// New creates a Bus.
+func New(opts ...Option) *Bus {
+	b := &Bus{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subscription 表示一个订阅
+// Subscription represents a subscription.
+type Subscription struct {
+	s *subscriber
+}
+
+// Unsubscribe 取消订阅，之后不会再有新的事件开始投递给该订阅者，异步订阅者队列中尚未处理的事件会被丢弃
+// 可以在处理函数中调用，重复调用不做任何事；返回时处理函数可能仍在执行
+// Unsubscribe cancels the subscription, no new delivery to the subscriber starts afterwards
+// and the events left in the queue of an asynchronous subscriber are discarded.
+// It can be called from within the handler and calling it again does nothing. The handler may still be running when it returns.
+func (s *Subscription) Unsubscribe() {
+	s.s.bus.remove(s.s)
+	s.s.stopOnce.Do(func() {
+		close(s.s.stop)
+	})
+}
+
+type envelope struct {
+	topic string
+	event any
+}
+
+type subscriber struct {
+	bus     *Bus
+	pattern []string
+	accepts func(event any) bool
+	handle  func(topic string, event any)
+
+	// queue 为 nil 时同步投递
+	queue  chan envelope
+	policy OverflowPolicy
+
+	stop      chan struct{}
+	stopOnce  sync.Once
+	drain     chan struct{}
+	drainOnce sync.Once
+	done      chan struct{}
+}
+
+// Subscribe 订阅与 pattern 匹配的主题中类型为 T 的事件，其他类型的事件会被忽略
+// Subscribe subscribes to the events of type T in the topics matching pattern, events of other types are ignored.
+//
+// Parameters:
+//   - b: the bus
+//   - pattern: the topic pattern, which may contain wildcards
+//   - handler: the function that handles the events
+//   - opts: the options of the subscription
+//
+// Returns:
+//   - *Subscription: the subscription
+//   - error: ErrInvalidTopic if pattern is invalid, ErrBusClosed if the bus is closed
+//
+// 参数：
+//   - b：事件总线
+//   - pattern：主题的模式，可以包含通配符
+//   - handler：处理事件的函数
+//   - opts：订阅的可选配置
+//
+// 返回值：
+//   - *Subscription：订阅
+//   - error：pattern 不合法时返回 ErrInvalidTopic，事件总线已经关闭时返回 ErrBusClosed
+func Subscribe[T any](b *Bus, pattern string, handler func(topic string, event T), opts ...SubscribeOption) (*Subscription, error) {
+	segments, ok := parsePattern(pattern)
+	if !ok {
+		return nil, ErrInvalidTopic
+	}
+	s := &subscriber{
+		bus:     b,
+		pattern: segments,
+		accepts: func(event any) bool {
+			_, ok := event.(T)
+			return ok
+		},
+		handle: func(topic string, event any) {
+			handler(topic, event.(T))
+		},
+		stop:  make(chan struct{}),
+		drain: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+	// 写时复制，发布者可以在不持有锁的情况下遍历订阅者
+	subs := make([]*subscriber, len(b.subs), len(b.subs)+1)
+	copy(subs, b.subs)
+	b.subs = append(subs, s)
+	if s.queue != nil {
+		go s.run()
+	} else {
+		close(s.done)
+	}
+	return &Subscription{s: s}, nil
+}
+
+// Publish 将事件发布到 topic，topic 不能包含通配符
+// 同步订阅者在当前 goroutine 中依次处理事件；使用 Block 策略的异步订阅者队列已满时会阻塞，ctx 结束时放弃投递并最终返回 ctx.Err()
+// Publish publishes the event to topic, which must not contain wildcards.
+// Synchronous subscribers handle the event one by one in the calling goroutine. It blocks while the queue of an asynchronous subscriber
+// with the Block policy is full, and if ctx is done it gives up the delivery and eventually returns ctx.Err().
+func (b *Bus) Publish(ctx context.Context, topic string, event any) error {
+	segments, ok := parseTopic(topic)
+	if !ok {
+		return ErrInvalidTopic
+	}
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	b.publishing.Add(1)
+	subs := b.subs
+	b.mu.RUnlock()
+	defer b.publishing.Done()
+
+	var err error
+	env := envelope{topic: topic, event: event}
+	for _, s := range subs {
+		if !match(s.pattern, segments) || !s.accepts(event) {
+			continue
+		}
+		if s.queue == nil {
+			if !s.stopped() {
+				b.invoke(s, env)
+			}
+			continue
+		}
+		if e := b.enqueue(ctx, s, env); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Close 关闭事件总线，之后的 Publish 和 Subscribe 返回 ErrBusClosed；Close 等待正在进行的发布完成，以及异步订阅者处理完队列中的事件
+// ctx 先结束时返回 ctx.Err()，剩余的事件会被丢弃；重复调用不做任何事
+// Close closes the bus, later calls to Publish and Subscribe return ErrBusClosed.
+// It waits for the ongoing publishes to finish and the asynchronous subscribers to handle the events in their queues.
+// If ctx is done first, it returns ctx.Err() and the remaining events are discarded. Calling it again does nothing.
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = nil
+	b.mu.Unlock()
+
+	stopAll := func() {
+		for _, s := range subs {
+			s.stopOnce.Do(func() {
+				close(s.stop)
+			})
+		}
+	}
+	published := make(chan struct{})
+	go func() {
+		b.publishing.Wait()
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-ctx.Done():
+		stopAll()
+		return ctx.Err()
+	}
+	for _, s := range subs {
+		s.drainOnce.Do(func() {
+			close(s.drain)
+		})
+	}
+	for _, s := range subs {
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			stopAll()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (b *Bus) remove(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, sub := range b.subs {
+		if sub == s {
+			subs := make([]*subscriber, 0, len(b.subs)-1)
+			subs = append(subs, b.subs[:i]...)
+			b.subs = append(subs, b.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *Bus) enqueue(ctx context.Context, s *subscriber, env envelope) error {
+	switch s.policy {
+	case Block:
+		select {
+		case s.queue <- env:
+		case <-s.stop:
+		case <-s.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case DropOldest:
+		// 无缓冲队列中没有可丢弃的旧事件，按 DropNewest 处理
+		if cap(s.queue) == 0 {
+			return b.dropNewest(s, env)
+		}
+		for {
+			select {
+			case s.queue <- env:
+				return nil
+			case <-s.stop:
+				return nil
+			case <-s.done:
+				return nil
+			default:
+			}
+			select {
+			case old := <-s.queue:
+				b.report(old.topic, ErrEventDropped)
+			default:
+			}
+		}
+	default:
+		return b.dropNewest(s, env)
+	}
+	return nil
+}
+
+// dropNewest 尝试投递事件，队列已满时丢弃该事件
+func (b *Bus) dropNewest(s *subscriber, env envelope) error {
+	select {
+	case s.queue <- env:
+	default:
+		b.report(env.topic, ErrEventDropped)
+	}
+	return nil
+}
+
+// invoke 调用订阅者的处理函数，并将其中发生的 panic 报告给错误回调
+func (b *Bus) invoke(s *subscriber, env envelope) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.report(env.topic, &syncx.PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			})
+		}
+	}()
+	s.handle(env.topic, env.event)
+}
+
+func (b *Bus) report(topic string, err error) {
+	if b.onError != nil {
+		b.onError(topic, err)
+	}
+}
+
+func (s *subscriber) run() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case env := <-s.queue:
+			if s.stopped() {
+				return
+			}
+			s.bus.invoke(s, env)
+		case <-s.drain:
+			for {
+				select {
+				case <-s.stop:
+					return
+				case env := <-s.queue:
+					s.bus.invoke(s, env)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *subscriber) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseTopic 解析发布的主题，主题不能为空，不能包含空段或通配符
+func parseTopic(topic string) ([]string, bool) {
+	segments := strings.Split(topic, ".")
+	for _, seg := range segments {
+		if seg == "" || seg == "*" || seg == "#" {
+			return nil, false
+		}
+	}
+	return segments, true
+}
+
+// parsePattern 解析订阅的模式，模式不能为空，不能包含空段，"#" 只能是最后一段
+func parsePattern(pattern string) ([]string, bool) {
+	segments := strings.Split(pattern, ".")
+	for i, seg := range segments {
+		if seg == "" || (seg == "#" && i != len(segments)-1) {
+			return nil, false
+		}
+	}
+	return segments, true
+}
+
+// match 判断主题是否与模式匹配
+func match(pattern, topic []string) bool {
+	for i, p := range pattern {
+		if p == "#" {
+			return true
+		}
+		if i >= len(topic) || (p != "*" && p != topic[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
@@ -0,0 +1,371 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenmingyong0423/gkit/syncx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderCreated struct {
+	ID int
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{
+			name:    "完全相同",
+			pattern: "order.created",
+			topic:   "order.created",
+			want:    true,
+		},
+		{
+			name:    "不同",
+			pattern: "order.created",
+			topic:   "order.paid",
+		},
+		{
+			name:    "* 匹配一段",
+			pattern: "order.*",
+			topic:   "order.paid",
+			want:    true,
+		},
+		{
+			name:    "* 不匹配多段",
+			pattern: "order.*",
+			topic:   "order.paid.online",
+		},
+		{
+			name:    "* 不匹配零段",
+			pattern: "order.*",
+			topic:   "order",
+		},
+		{
+			name:    "# 匹配多段",
+			pattern: "order.#",
+			topic:   "order.paid.online",
+			want:    true,
+		},
+		{
+			name:    "# 匹配零段",
+			pattern: "order.#",
+			topic:   "order",
+			want:    true,
+		},
+		{
+			name:    "# 匹配所有主题",
+			pattern: "#",
+			topic:   "user.created",
+			want:    true,
+		},
+		{
+			name:    "模式更长",
+			pattern: "order.created.online",
+			topic:   "order.created",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, ok := parsePattern(tt.pattern)
+			require.True(t, ok)
+			topic, ok := parseTopic(tt.topic)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, match(pattern, topic))
+		})
+	}
+}
+
+func TestInvalidTopic(t *testing.T) {
+	b := New()
+	for _, pattern := range []string{"", "order.", "order..created", "#.created"} {
+		_, err := Subscribe(b, pattern, func(topic string, event int) {})
+		assert.Equal(t, ErrInvalidTopic, err, pattern)
+	}
+	for _, topic := range []string{"", "order.", "order.*", "order.#"} {
+		assert.Equal(t, ErrInvalidTopic, b.Publish(context.Background(), topic, 1), topic)
+	}
+}
+
+func TestBus_Sync(t *testing.T) {
+	b := New()
+	var (
+		orders []int
+		topics []string
+	)
+	_, err := Subscribe(b, "order.*", func(topic string, event orderCreated) {
+		orders = append(orders, event.ID)
+	})
+	require.NoError(t, err)
+	sub, err := Subscribe(b, "#", func(topic string, event any) {
+		topics = append(topics, topic)
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.Publish(context.Background(), "order.created", orderCreated{ID: 1}))
+	// 类型不匹配的事件被忽略
+	require.NoError(t, b.Publish(context.Background(), "order.created", "not an order"))
+	require.NoError(t, b.Publish(context.Background(), "user.created", orderCreated{ID: 2}))
+	assert.Equal(t, []int{1}, orders)
+	assert.Equal(t, []string{"order.created", "order.created", "user.created"}, topics)
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	require.NoError(t, b.Publish(context.Background(), "order.paid", orderCreated{ID: 3}))
+	assert.Equal(t, []int{1, 3}, orders)
+	assert.Len(t, topics, 3)
+}
+
+func TestBus_UnsubscribeInHandler(t *testing.T) {
+	b := New()
+	count := 0
+	var sub *Subscription
+	sub, err := Subscribe(b, "tick", func(topic string, event int) {
+		count++
+		sub.Unsubscribe()
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.Publish(context.Background(), "tick", 1))
+	require.NoError(t, b.Publish(context.Background(), "tick", 2))
+	assert.Equal(t, 1, count)
+}
+
+func TestBus_Panic(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	b := New(WithErrorHandler(func(topic string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}))
+	_, err := Subscribe(b, "a", func(topic string, event int) {
+		panic("boom")
+	})
+	require.NoError(t, err)
+	called := false
+	_, err = Subscribe(b, "a", func(topic string, event int) {
+		called = true
+	})
+	require.NoError(t, err)
+
+	// 一个订阅者 panic 不影响其他订阅者
+	require.NoError(t, b.Publish(context.Background(), "a", 1))
+	assert.True(t, called)
+	require.Len(t, errs, 1)
+	var panicErr *syncx.PanicError
+	require.ErrorAs(t, errs[0], &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+}
+
+func TestBus_Async(t *testing.T) {
+	b := New()
+	var (
+		mu  sync.Mutex
+		got []int
+	)
+	_, err := Subscribe(b, "a", func(topic string, event int) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, event)
+	}, WithAsync(100, Block))
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, b.Publish(context.Background(), "a", i))
+	}
+	// 关闭时等待队列中的事件处理完
+	require.NoError(t, b.Close(context.Background()))
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i
+	}
+	assert.Equal(t, want, got)
+
+	assert.Equal(t, ErrBusClosed, b.Publish(context.Background(), "a", 1))
+	_, err = Subscribe(b, "a", func(topic string, event int) {})
+	assert.Equal(t, ErrBusClosed, err)
+	assert.NoError(t, b.Close(context.Background()))
+}
+
+func TestBus_OverflowPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      OverflowPolicy
+		wantGot     []int
+		wantDropped int
+	}{
+		{
+			name:        "丢弃新事件",
+			policy:      DropNewest,
+			wantGot:     []int{0, 1, 2},
+			wantDropped: 2,
+		},
+		{
+			name:        "丢弃旧事件",
+			policy:      DropOldest,
+			wantGot:     []int{0, 3, 4},
+			wantDropped: 2,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				dropped int
+				got     []int
+			)
+			b := New(WithErrorHandler(func(topic string, err error) {
+				assert.Equal(t, ErrEventDropped, err)
+				mu.Lock()
+				defer mu.Unlock()
+				dropped++
+			}))
+			started := make(chan struct{})
+			release := make(chan struct{})
+			_, err := Subscribe(b, "a", func(topic string, event int) {
+				if event == 0 {
+					close(started)
+					<-release
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, event)
+			}, WithAsync(2, tt.policy))
+			require.NoError(t, err)
+
+			require.NoError(t, b.Publish(context.Background(), "a", 0))
+			<-started
+			for i := 1; i <= 4; i++ {
+				require.NoError(t, b.Publish(context.Background(), "a", i))
+			}
+			close(release)
+			require.NoError(t, b.Close(context.Background()))
+			assert.Equal(t, tt.wantGot, got)
+			assert.Equal(t, tt.wantDropped, dropped)
+		})
+	}
+}
+
+func TestBus_ZeroBuffer(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy OverflowPolicy
+	}{
+		{
+			name:   "丢弃新事件",
+			policy: DropNewest,
+		},
+		{
+			name:   "丢弃旧事件",
+			policy: DropOldest,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				dropped int
+				got     []int
+			)
+			b := New(WithErrorHandler(func(topic string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				dropped++
+			}))
+			release := make(chan struct{})
+			_, err := Subscribe(b, "a", func(topic string, event int) {
+				<-release
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, event)
+			}, WithAsync(0, tt.policy))
+			require.NoError(t, err)
+
+			// 订阅者未就绪时发布者不会阻塞，而是丢弃新事件
+			published := make(chan struct{})
+			go func() {
+				defer close(published)
+				for i := 1; i <= 3; i++ {
+					assert.NoError(t, b.Publish(context.Background(), "a", i))
+				}
+			}()
+			select {
+			case <-published:
+			case <-time.After(time.Second):
+				t.Fatal("publish blocked")
+			}
+			close(release)
+			require.NoError(t, b.Close(context.Background()))
+			assert.LessOrEqual(t, len(got), 1)
+			assert.Equal(t, 3, len(got)+dropped)
+		})
+	}
+}
+
+func TestBus_Block(t *testing.T) {
+	b := New()
+	release := make(chan struct{})
+	sub, err := Subscribe(b, "a", func(topic string, event int) {
+		<-release
+	}, WithAsync(1, Block))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Publish(context.Background(), "a", 1))
+	require.NoError(t, b.Publish(context.Background(), "a", 2))
+	// 队列已满时阻塞直到 ctx 结束
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Publish(ctx, "a", 3))
+
+	// 取消订阅后阻塞的发布者被唤醒
+	done := make(chan error)
+	go func() {
+		done <- b.Publish(context.Background(), "a", 4)
+	}()
+	sub.Unsubscribe()
+	assert.NoError(t, <-done)
+	close(release)
+	assert.NoError(t, b.Close(context.Background()))
+}
+
+func TestBus_CloseTimeout(t *testing.T) {
+	b := New()
+	release := make(chan struct{})
+	defer close(release)
+	_, err := Subscribe(b, "a", func(topic string, event int) {
+		<-release
+	}, WithAsync(10, Block))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, b.Publish(context.Background(), "a", i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Close(ctx))
+}
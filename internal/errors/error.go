@@ -19,3 +19,39 @@ import "fmt"
 func NewIndexOutOfRange(length, index int) error {
 	return fmt.Errorf("gkit: index out of range, length: %d, index: %d", length, index)
 }
+
+func NewEmptySlice() error {
+	return fmt.Errorf("gkit: slice is empty")
+}
+
+func NewSampleSizeOutOfRange(length, size int) error {
+	return fmt.Errorf("gkit: sample size out of range, length: %d, size: %d", length, size)
+}
+
+func NewLengthMismatch(itemsLength, weightsLength int) error {
+	return fmt.Errorf("gkit: length mismatch, items length: %d, weights length: %d", itemsLength, weightsLength)
+}
+
+func NewInvalidWeight(index int, weight float64) error {
+	return fmt.Errorf("gkit: invalid weight, index: %d, weight: %v", index, weight)
+}
+
+func NewNonPositiveTotalWeight(total float64) error {
+	return fmt.Errorf("gkit: total weight must be positive, total: %v", total)
+}
+
+func NewInvalidPageSize(size int) error {
+	return fmt.Errorf("gkit: invalid page size, size: %d", size)
+}
+
+func NewPageOutOfRange(pages, page int) error {
+	return fmt.Errorf("gkit: page out of range, pages: %d, page: %d", pages, page)
+}
+
+func NewInvalidCursor(cursor string) error {
+	return fmt.Errorf("gkit: invalid cursor, cursor: %q", cursor)
+}
+
+func NewInvalidCursorKey(key any) error {
+	return fmt.Errorf("gkit: invalid cursor key, key: %v", key)
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff 返回第 attempt 次尝试失败后（attempt 从 1 开始）到下一次尝试前需要等待的时间，prev 是上一次等待的时间，第一次失败时为 0
+// Backoff returns how long to wait after the attempt-th attempt failed (attempt starts from 1) before the next attempt.
+// prev is the previous delay, which is 0 after the first failure.
+type Backoff func(attempt int, prev time.Duration) time.Duration
+
+// Constant 返回每次都等待 d 的退避策略
+// Constant returns a backoff that always waits d.
+func Constant(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return d
+	}
+}
+
+// Exponential 返回指数退避策略，第 n 次失败后等待 base * 2^(n-1)，最多等待 maxDelay
+// Exponential returns an exponential backoff that waits base * 2^(n-1) after the n-th failure, and at most maxDelay.
+//
+// Parameters:
+//   - base: the delay after the first failure
+//   - maxDelay: the upper bound of the delay
+//
+// 参数：
+//   - base：第一次失败后等待的时间
+//   - maxDelay：等待时间的上限
+func Exponential(base, maxDelay time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < maxDelay; i++ {
+			if d > maxDelay/2 {
+				return maxDelay
+			}
+			d *= 2
+		}
+		return min(d, maxDelay)
+	}
+}
+
+// DecorrelatedJitter 返回去相关抖动的退避策略，每次等待 [base, prev * 3) 之间的随机时间，最多等待 maxDelay，可以避免大量调用者同时重试
+// r 为 nil 时使用 math/rand 的默认随机数源；r 不是并发安全的，传入非 nil 的 r 时该退避策略不能在多个 goroutine 中同时使用
+// DecorrelatedJitter returns a decorrelated jitter backoff that waits a random delay in [base, prev * 3) and at most maxDelay,
+// which prevents a large number of callers from retrying at the same time.
+// The default source of math/rand is used if r is nil. r is not safe for concurrent use,
+// so the backoff must not be used by multiple goroutines at the same time if r is not nil.
+//
+// Parameters:
+//   - base: the minimum delay
+//   - maxDelay: the upper bound of the delay
+//   - r: the source of randomness
+//
+// 参数：
+//   - base：最小等待时间
+//   - maxDelay：等待时间的上限
+//   - r：随机数源
+func DecorrelatedJitter(base, maxDelay time.Duration, r *rand.Rand) Backoff {
+	return func(_ int, prev time.Duration) time.Duration {
+		upper := max(prev, base) * 3
+		if upper <= base {
+			return min(base, maxDelay)
+		}
+		return min(base+time.Duration(randInt63n(r, int64(upper-base))), maxDelay)
+	}
+}
+
+// randInt63n 在 r 为 nil 时使用 math/rand 的默认随机数源
+func randInt63n(r *rand.Rand, n int64) int64 {
+	if r == nil {
+		return rand.Int63n(n)
+	}
+	return r.Int63n(n)
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstant(t *testing.T) {
+	b := Constant(time.Second)
+	assert.Equal(t, time.Second, b(1, 0))
+	assert.Equal(t, time.Second, b(10, time.Second))
+}
+
+func TestExponential(t *testing.T) {
+	testCases := []struct {
+		name     string
+		base     time.Duration
+		maxDelay time.Duration
+		attempts []int
+		want     []time.Duration
+	}{
+		{
+			name:     "指数增长",
+			base:     100 * time.Millisecond,
+			maxDelay: time.Second,
+			attempts: []int{1, 2, 3, 4, 5, 100},
+			want: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+				800 * time.Millisecond,
+				time.Second,
+				time.Second,
+			},
+		},
+		{
+			name:     "base 超过上限",
+			base:     2 * time.Second,
+			maxDelay: time.Second,
+			attempts: []int{1, 2},
+			want:     []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:     "上限很大时不会溢出",
+			base:     time.Second,
+			maxDelay: time.Duration(1<<63 - 1),
+			attempts: []int{64, 1000},
+			want:     []time.Duration{time.Duration(1<<63 - 1), time.Duration(1<<63 - 1)},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Exponential(tt.base, tt.maxDelay)
+			got := make([]time.Duration, 0, len(tt.attempts))
+			for _, attempt := range tt.attempts {
+				got = append(got, b(attempt, 0))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	base := 100 * time.Millisecond
+	maxDelay := 2 * time.Second
+	b := DecorrelatedJitter(base, maxDelay, rand.New(rand.NewSource(1)))
+
+	var prev time.Duration
+	for attempt := 1; attempt <= 100; attempt++ {
+		d := b(attempt, prev)
+		assert.GreaterOrEqual(t, d, base)
+		assert.LessOrEqual(t, d, maxDelay)
+		assert.Less(t, d, max(prev, base)*3)
+		prev = d
+	}
+
+	// 使用默认随机数源
+	d := DecorrelatedJitter(base, maxDelay, nil)(1, 0)
+	assert.GreaterOrEqual(t, d, base)
+	assert.Less(t, d, 3*base)
+
+	assert.Equal(t, time.Duration(0), DecorrelatedJitter(0, time.Second, nil)(1, 0))
+}
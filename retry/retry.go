@@ -0,0 +1,151 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry retries operations that may fail temporarily, with configurable backoff policies,
+// attempt and elapsed time limits, and retryable error classification.
+//
+// retry 包用于重试可能暂时失败的操作，支持可配置的退避策略、尝试次数和耗时上限以及可重试错误的判断。
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chenmingyong0423/gkit/syncx"
+)
+
+// Option 是重试的可选配置
+// Option configures the retries.
+type Option func(o *options)
+
+type options struct {
+	backoff        Backoff
+	maxAttempts    int
+	maxElapsedTime time.Duration
+	retryIf        func(err error) bool
+	onRetry        func(attempt int, err error, delay time.Duration)
+	clock          syncx.Clock
+}
+
+// WithBackoff 设置退避策略，默认为 Exponential(100ms, 10s)
+// WithBackoff sets the backoff policy, Exponential(100ms, 10s) by default.
+func WithBackoff(backoff Backoff) Option {
+	return func(o *options) {
+		o.backoff = backoff
+	}
+}
+
+// WithMaxAttempts 设置最多尝试的次数（包括第一次），n 小于等于 0 表示不限制次数，默认为 3
+// WithMaxAttempts sets the maximum number of attempts including the first one, n <= 0 means no limit. 3 by default.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithMaxElapsedTime 设置从第一次尝试开始允许的最长耗时，下一次尝试会超过该时间时停止重试，d 小于等于 0 表示不限制，默认不限制
+// WithMaxElapsedTime sets the maximum time allowed since the first attempt, the retries stop if the next attempt would exceed it.
+// d <= 0 means no limit, which is the default.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(o *options) {
+		o.maxElapsedTime = d
+	}
+}
+
+// WithRetryIf 设置判断错误是否可以重试的函数，返回 false 时立即停止重试，默认所有错误都可以重试
+// WithRetryIf sets the function that decides whether an error is retryable, the retries stop immediately if it returns false.
+// Every error is retryable by default.
+func WithRetryIf(retryIf func(err error) bool) Option {
+	return func(o *options) {
+		o.retryIf = retryIf
+	}
+}
+
+// WithOnRetry 设置每次尝试失败且即将重试时的回调，attempt 是失败的尝试次数（从 1 开始），delay 是下一次尝试前等待的时间
+// WithOnRetry sets the callback invoked when an attempt fails and will be retried,
+// attempt is the number of the failed attempt starting from 1 and delay is how long to wait before the next attempt.
+func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(o *options) {
+		o.onRetry = fn
+	}
+}
+
+// WithClock 设置等待使用的时钟，默认为 syncx.SystemClock
+// WithClock sets the clock used for waiting, syncx.SystemClock by default.
+func WithClock(clock syncx.Clock) Option {
+	return func(o *options) {
+		if clock == nil {
+			clock = syncx.SystemClock
+		}
+		o.clock = clock
+	}
+}
+
+// Do 执行 fn，失败时按照配置等待并重试，直到 fn 成功、错误不可重试、达到尝试次数或耗时上限，或 ctx 结束
+// 停止重试时返回 fn 最后一次返回的错误；等待期间 ctx 结束时返回同时包装了 ctx.Err() 和最后一次错误的错误
+// Do runs fn and waits and retries it as configured when it fails, until fn succeeds, the error is not retryable,
+// the attempt or elapsed time limit is reached, or ctx is done.
+// When the retries stop, the last error returned by fn is returned. If ctx is done while waiting,
+// the returned error wraps both ctx.Err() and the last error.
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// DoValue 类似 Do，但返回 fn 成功时的返回值
+// DoValue is like Do but returns the value of fn when it succeeds.
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	o := options{
+		backoff:     Exponential(100*time.Millisecond, 10*time.Second),
+		maxAttempts: 3,
+		retryIf: func(error) bool {
+			return true
+		},
+		clock: syncx.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	start := o.clock.Now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+		val, err := fn(ctx)
+		if err == nil || !o.retryIf(err) {
+			return val, err
+		}
+		if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			return val, err
+		}
+		delay = max(o.backoff(attempt, delay), 0)
+		if o.maxElapsedTime > 0 && o.clock.Now().Add(delay).Sub(start) > o.maxElapsedTime {
+			return val, err
+		}
+		if o.onRetry != nil {
+			o.onRetry(attempt, err, delay)
+		}
+		select {
+		case <-o.clock.After(delay):
+		case <-ctx.Done():
+			return val, fmt.Errorf("%w, last error: %w", ctx.Err(), err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chenmingyong0423/gkit/syncx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	mockErr := errors.New("mock error")
+	permanentErr := errors.New("permanent error")
+	testCases := []struct {
+		name string
+		opts []Option
+		// errs 是每次尝试返回的错误，超出部分返回 nil
+		errs         []error
+		wantErr      error
+		wantAttempts int
+		wantDelays   []time.Duration
+	}{
+		{
+			name:         "第一次就成功",
+			wantAttempts: 1,
+		},
+		{
+			name:         "重试后成功",
+			errs:         []error{mockErr, mockErr},
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:         "达到最大尝试次数",
+			errs:         []error{mockErr, mockErr, mockErr, mockErr},
+			wantErr:      mockErr,
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:         "不限制尝试次数",
+			opts:         []Option{WithMaxAttempts(0), WithBackoff(Constant(time.Second))},
+			errs:         []error{mockErr, mockErr, mockErr, mockErr},
+			wantAttempts: 5,
+			wantDelays:   []time.Duration{time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			name: "错误不可重试",
+			opts: []Option{WithRetryIf(func(err error) bool {
+				return !errors.Is(err, permanentErr)
+			})},
+			errs:         []error{mockErr, permanentErr},
+			wantErr:      permanentErr,
+			wantAttempts: 2,
+			wantDelays:   []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name: "达到耗时上限",
+			opts: []Option{
+				WithMaxAttempts(0),
+				WithBackoff(Constant(time.Second)),
+				WithMaxElapsedTime(2500 * time.Millisecond),
+			},
+			errs:         []error{mockErr, mockErr, mockErr, mockErr},
+			wantErr:      mockErr,
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{time.Second, time.Second},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			var delays []time.Duration
+			opts := append([]Option{
+				WithClock(newAutoClock()),
+				WithOnRetry(func(attempt int, err error, delay time.Duration) {
+					assert.Equal(t, attempts, attempt)
+					assert.Equal(t, tt.errs[attempt-1], err)
+					delays = append(delays, delay)
+				}),
+			}, tt.opts...)
+			err := Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			}, opts...)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+			assert.Equal(t, tt.wantDelays, delays)
+		})
+	}
+}
+
+func TestDo_Context(t *testing.T) {
+	mockErr := errors.New("mock error")
+	clock := syncx.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Do(ctx, func(ctx context.Context) error {
+			return mockErr
+		}, WithClock(clock))
+	}()
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	err := <-done
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, mockErr)
+
+	// ctx 已经结束时不执行 fn
+	called := false
+	err = Do(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, called)
+}
+
+func TestDoValue(t *testing.T) {
+	attempts := 0
+	val, err := DoValue(context.Background(), func(ctx context.Context) (int, error) {
+		attempts++
+		if attempts < 3 {
+			return 0, errors.New("mock error")
+		}
+		return 42, nil
+	}, WithClock(newAutoClock()))
+	require.NoError(t, err)
+	assert.Equal(t, 42, val)
+	assert.Equal(t, 3, attempts)
+}
+
+// autoClock 是 After 立即返回并推进当前时间的时钟
+type autoClock struct {
+	now time.Time
+}
+
+func newAutoClock() *autoClock {
+	return &autoClock{
+		now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (c *autoClock) Now() time.Time {
+	return c.now
+}
+
+func (c *autoClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
@@ -24,17 +24,21 @@ import (
 // - index: The index of the item to be deleted.
 //
 // Returns:
-// - If the index value is within the valid range, a new slice containing only the elements that were not deleted is returned.
+// - If the index value is within the valid range, the compacted slice containing only the elements that were not deleted is returned.
 // - If the index value is out of range, a nil slice and an IndexOutOfRange error are returned.
 //
+// Note: The result shares the backing array with data, which is modified in place. Use WithoutIndex to keep data unchanged.
+//
 // DeleteByIndex 用于删除给定类型的切片中特定索引的元素。
 // 参数：
 // - data：待操作的切片。
 // - index：待删除元素的索引。
 //
 // 返回值：
-// - 如果索引值在有效范围内，则返回一个新的切片，其中仅包含没有被删除的元素。
+// - 如果索引值在有效范围内，则返回压缩后的切片，其中仅包含没有被删除的元素。
 // - 如果索引值不在有效范围内，则返回一个nil切片和一个IndexOutOfRange错误。
+//
+// 注意：返回的切片与 data 共享底层数组，data 会被原地修改，如需保持 data 不变请使用 WithoutIndex。
 func DeleteByIndex[T any](data []T, index int) ([]T, error) {
 	length := len(data)
 	if index < 0 || index >= length {
@@ -51,7 +55,9 @@ func DeleteByIndex[T any](data []T, index int) ([]T, error) {
 // - dstItem: The item to be deleted.
 //
 // Returns:
-// - A new slice containing only the elements that were not deleted.
+// - The compacted slice containing only the elements that were not deleted.
+//
+// Note: The result shares the backing array with data, which is modified in place. Use Without to keep data unchanged.
 //
 // DeleteByItem 用于删除给定类型的切片中指定的元素。
 // 参数：
@@ -59,7 +65,9 @@ func DeleteByIndex[T any](data []T, index int) ([]T, error) {
 // - dstItem：待删除元素。
 //
 // 返回值：
-// - 一个新的切片，其中仅包含没有被删除的元素。
+// - 压缩后的切片，其中仅包含没有被删除的元素。
+//
+// 注意：返回的切片与 data 共享底层数组，data 会被原地修改，如需保持 data 不变请使用 Without。
 func DeleteByItem[T comparable](data []T, dstItem T) []T {
 	return DeleteByFilterFunc[T](data, func(idx int, srcItem T) bool {
 		return dstItem == srcItem
@@ -72,7 +80,10 @@ func DeleteByItem[T comparable](data []T, dstItem T) []T {
 // - filterFunc: The function that represents the filter condition.
 //
 // Returns:
-// - A new slice containing only the elements that were not filtered out by the filter function.
+// - The compacted slice containing only the elements that were not filtered out by the filter function.
+//
+// Note: The result shares the backing array with data, which is modified in place, and the vacated tail of data
+// is set to zero values so that it does not retain references. Use WithoutFunc to keep data unchanged.
 //
 // DeleteByFilterFunc 是一个通用函数，用于删除给定类型的切片中符合特定条件的元素。
 // 参数：
@@ -80,7 +91,10 @@ func DeleteByItem[T comparable](data []T, dstItem T) []T {
 // - filterFunc：过滤条件的函数。
 //
 // 返回值：
-// - 一个新的切片，其中仅包含没有被过滤器函数过滤掉的元素。
+// - 压缩后的切片，其中仅包含没有被过滤器函数过滤掉的元素。
+//
+// 注意：返回的切片与 data 共享底层数组，data 会被原地修改，其腾出的尾部元素会被置为零值，避免继续持有引用，
+// 如需保持 data 不变请使用 WithoutFunc。
 func DeleteByFilterFunc[T any](data []T, filterFunc filterFunc[T]) []T {
 	pos := 0
 	for idx := range data {
@@ -90,5 +104,177 @@ func DeleteByFilterFunc[T any](data []T, filterFunc filterFunc[T]) []T {
 		data[pos] = data[idx]
 		pos++
 	}
+	clear(data[pos:])
 	return data[:pos]
 }
+
+// WithoutIndex is the copy-based variant of DeleteByIndex, which deletes the element at the given index.
+// Parameters:
+// - data: The slice to operate on, which is not modified.
+// - index: The index of the item to be deleted.
+//
+// Returns:
+// - If the index value is within the valid range, a new slice containing only the elements that were not deleted is returned.
+// - If the index value is out of range, a nil slice and an IndexOutOfRange error are returned.
+//
+// WithoutIndex 是 DeleteByIndex 基于复制的版本，用于删除切片中特定索引的元素。
+// 参数：
+// - data：待操作的切片，不会被修改。
+// - index：待删除元素的索引。
+//
+// 返回值：
+// - 如果索引值在有效范围内，则返回一个新的切片，其中仅包含没有被删除的元素。
+// - 如果索引值不在有效范围内，则返回一个nil切片和一个IndexOutOfRange错误。
+func WithoutIndex[T any](data []T, index int) ([]T, error) {
+	if err := checkIndex(len(data), index); err != nil {
+		return nil, err
+	}
+	return DeleteRange(data, index, index+1)
+}
+
+// Without is the copy-based variant of DeleteByItem, which deletes all the occurrences of the given items.
+// Parameters:
+// - data: The slice to operate on, which is not modified.
+// - items: The items to be deleted.
+//
+// Returns:
+// - A new slice containing only the elements that were not deleted.
+//
+// Without 是 DeleteByItem 基于复制的版本，用于删除切片中所有与给定元素相等的元素。
+// 参数：
+// - data：待操作的切片，不会被修改。
+// - items：待删除元素。
+//
+// 返回值：
+// - 一个新的切片，其中仅包含没有被删除的元素。
+func Without[T comparable](data []T, items ...T) []T {
+	deleted := toMap(items)
+	return WithoutFunc(data, func(idx int, item T) bool {
+		_, ok := deleted[item]
+		return ok
+	})
+}
+
+// WithoutFunc is the copy-based variant of DeleteByFilterFunc, which deletes the elements that meet the filter condition.
+// Parameters:
+// - data: The slice to operate on, which is not modified.
+// - filterFunc: The function that represents the filter condition.
+//
+// Returns:
+// - A new slice containing only the elements that were not filtered out by the filter function.
+//
+// WithoutFunc 是 DeleteByFilterFunc 基于复制的版本，用于删除切片中符合特定条件的元素。
+// 参数：
+// - data：待操作的切片，不会被修改。
+// - filterFunc：过滤条件的函数。
+//
+// 返回值：
+// - 一个新的切片，其中仅包含没有被过滤器函数过滤掉的元素。
+func WithoutFunc[T any](data []T, filterFunc filterFunc[T]) []T {
+	result := make([]T, 0, len(data))
+	for idx, item := range data {
+		if !filterFunc(idx, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// DeleteRange is used to delete the elements in the index range [from, to) of a slice.
+// Parameters:
+// - data: The slice to operate on, which is not modified.
+// - from: The start index of the range, inclusive.
+// - to: The end index of the range, exclusive.
+//
+// Returns:
+// - If 0 <= from <= to <= len(data), a new slice containing only the elements that were not deleted is returned.
+// - Otherwise, a nil slice and an IndexOutOfRange error of the invalid index are returned.
+//
+// DeleteRange 用于删除切片中索引范围 [from, to) 内的元素。
+// 参数：
+// - data：待操作的切片，不会被修改。
+// - from：范围的起始索引，包含在内。
+// - to：范围的结束索引，不包含在内。
+//
+// 返回值：
+// - 如果 0 <= from <= to <= len(data)，则返回一个新的切片，其中仅包含没有被删除的元素。
+// - 否则返回一个nil切片和不合法索引对应的IndexOutOfRange错误。
+func DeleteRange[T any](data []T, from, to int) ([]T, error) {
+	if err := checkRange(len(data), from, to); err != nil {
+		return nil, err
+	}
+	result := make([]T, 0, len(data)-(to-from))
+	result = append(result, data[:from]...)
+	return append(result, data[to:]...), nil
+}
+
+// DeleteRangeInplace 在原始切片上删除索引范围 [from, to) 内的元素，腾出的尾部元素会被置为零值
+// 索引范围不合法时返回一个nil切片和一个IndexOutOfRange错误
+func DeleteRangeInplace[T any](data []T, from, to int) ([]T, error) {
+	if err := checkRange(len(data), from, to); err != nil {
+		return nil, err
+	}
+	n := copy(data[from:], data[to:])
+	clear(data[from+n:])
+	return data[:from+n], nil
+}
+
+// DeleteIndexes is used to delete the elements at the given indexes of a slice, duplicate indexes are allowed.
+// Parameters:
+// - data: The slice to operate on, which is not modified.
+// - indexes: The indexes of the items to be deleted.
+//
+// Returns:
+// - If all the indexes are within the valid range, a new slice containing only the elements that were not deleted is returned.
+// - Otherwise, a nil slice and an IndexOutOfRange error of the first invalid index are returned.
+//
+// DeleteIndexes 用于删除切片中多个指定索引的元素，允许索引重复。
+// 参数：
+// - data：待操作的切片，不会被修改。
+// - indexes：待删除元素的索引。
+//
+// 返回值：
+// - 如果所有索引值都在有效范围内，则返回一个新的切片，其中仅包含没有被删除的元素。
+// - 否则返回一个nil切片和第一个不合法索引对应的IndexOutOfRange错误。
+func DeleteIndexes[T any](data []T, indexes ...int) ([]T, error) {
+	deleted, err := indexSet(len(data), indexes)
+	if err != nil {
+		return nil, err
+	}
+	return WithoutFunc(data, func(idx int, item T) bool {
+		_, ok := deleted[idx]
+		return ok
+	}), nil
+}
+
+// DeleteIndexesInplace 在原始切片上删除多个指定索引的元素，腾出的尾部元素会被置为零值
+// 任一索引值不在有效范围内时返回一个nil切片和一个IndexOutOfRange错误
+func DeleteIndexesInplace[T any](data []T, indexes ...int) ([]T, error) {
+	deleted, err := indexSet(len(data), indexes)
+	if err != nil {
+		return nil, err
+	}
+	return DeleteByFilterFunc(data, func(idx int, item T) bool {
+		_, ok := deleted[idx]
+		return ok
+	}), nil
+}
+
+// checkRange 检查索引范围 [from, to) 是否满足 0 <= from <= to <= length
+func checkRange(length, from, to int) error {
+	if from < 0 || from > length {
+		return errors.NewIndexOutOfRange(length, from)
+	}
+	if to < from || to > length {
+		return errors.NewIndexOutOfRange(length, to)
+	}
+	return nil
+}
+
+// indexSet 校验索引并将其转换为集合
+func indexSet(length int, indexes []int) (map[int]struct{}, error) {
+	if err := checkIndexes(length, indexes...); err != nil {
+		return nil, err
+	}
+	return toMap(indexes), nil
+}
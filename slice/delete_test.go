@@ -252,3 +252,223 @@ func TestDeleteByItem(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteRange(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		from    int
+		to      int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "from 小于 0",
+			data:    []int{1, 2, 3},
+			from:    -1,
+			to:      1,
+			wantErr: errors.NewIndexOutOfRange(3, -1),
+		},
+		{
+			name:    "to 大于长度",
+			data:    []int{1, 2, 3},
+			from:    1,
+			to:      4,
+			wantErr: errors.NewIndexOutOfRange(3, 4),
+		},
+		{
+			name:    "to 小于 from",
+			data:    []int{1, 2, 3},
+			from:    2,
+			to:      1,
+			wantErr: errors.NewIndexOutOfRange(3, 1),
+		},
+		{
+			name: "空范围",
+			data: []int{1, 2, 3},
+			from: 1,
+			to:   1,
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "删除中间范围",
+			data: []int{1, 2, 3, 4, 5},
+			from: 1,
+			to:   3,
+			want: []int{1, 4, 5},
+		},
+		{
+			name: "删除所有元素",
+			data: []int{1, 2, 3},
+			from: 0,
+			to:   3,
+			want: []int{},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := DeleteRange(tt.data, tt.from, tt.to)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, tt.data)
+
+			res, err = DeleteRangeInplace(tt.data, tt.from, tt.to)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			if err == nil {
+				// 腾出的尾部元素被置为零值
+				for _, item := range tt.data[len(res):] {
+					assert.Zero(t, item)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteIndexes(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		indexes []int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "下标越界",
+			data:    []int{1, 2, 3},
+			indexes: []int{0, 3},
+			wantErr: errors.NewIndexOutOfRange(3, 3),
+		},
+		{
+			name:    "不删除元素",
+			data:    []int{1, 2, 3},
+			indexes: nil,
+			want:    []int{1, 2, 3},
+		},
+		{
+			name:    "删除多个不连续元素",
+			data:    []int{1, 2, 3, 4, 5},
+			indexes: []int{4, 0, 2},
+			want:    []int{2, 4},
+		},
+		{
+			name:    "索引重复",
+			data:    []int{1, 2, 3},
+			indexes: []int{1, 1},
+			want:    []int{1, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := DeleteIndexes(tt.data, tt.indexes...)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, tt.data)
+
+			res, err = DeleteIndexesInplace(tt.data, tt.indexes...)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
+
+func TestDeleteByFilterFunc_ClearTail(t *testing.T) {
+	a, b, c := 1, 2, 3
+	data := []*int{&a, &b, &c}
+	res := DeleteByFilterFunc(data, func(idx int, item *int) bool {
+		return idx == 0
+	})
+	assert.Equal(t, []*int{&b, &c}, res)
+	// 腾出的尾部元素被置为零值，不再持有引用
+	assert.Nil(t, data[2])
+}
+
+func TestWithoutIndex(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		index   int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "下标越界，小于 0 的情况",
+			data:    []int{1, 2, 3, 4},
+			index:   -1,
+			wantErr: errors.NewIndexOutOfRange(4, -1),
+		},
+		{
+			name:    "切片为空的情况",
+			data:    []int{},
+			index:   0,
+			wantErr: errors.NewIndexOutOfRange(0, 0),
+		},
+		{
+			name:  "删除下标为 0 的元素",
+			data:  []int{1, 2, 3},
+			index: 0,
+			want:  []int{2, 3},
+		},
+		{
+			name:  "删除下标为 2 的元素",
+			data:  []int{1, 2, 3},
+			index: 2,
+			want:  []int{1, 2},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := WithoutIndex(tt.data, tt.index)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, tt.data)
+		})
+	}
+}
+
+func TestWithout(t *testing.T) {
+	testCases := []struct {
+		name  string
+		data  []int
+		items []int
+		want  []int
+	}{
+		{
+			name:  "nil 切片",
+			data:  nil,
+			items: []int{1},
+			want:  []int{},
+		},
+		{
+			name:  "删除不存在的元素",
+			data:  []int{2, 4, 6, 8},
+			items: []int{1},
+			want:  []int{2, 4, 6, 8},
+		},
+		{
+			name:  "删除多个元素",
+			data:  []int{2, 4, 2, 8, 6},
+			items: []int{2, 6},
+			want:  []int{4, 8},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			assert.Equal(t, tt.want, Without(tt.data, tt.items...))
+			assert.Equal(t, origin, clone(tt.data))
+		})
+	}
+}
+
+func TestWithoutFunc(t *testing.T) {
+	data := []int{0, 1, 2, 3, 4, 5}
+	res := WithoutFunc(data, func(idx int, item int) bool {
+		return item%2 == 0
+	})
+	assert.Equal(t, []int{1, 3, 5}, res)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, data)
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slice provides generic helpers for slices.
+//
+// Unless stated otherwise, the functions in this package never modify the input slice and always return
+// a newly allocated slice. The exceptions are the functions with the Inplace suffix and DeleteByIndex,
+// DeleteByItem and DeleteByFilterFunc, which modify the input slice in place and may return a slice that
+// shares its backing array. The vacated tail left by in-place deletion is set to zero values so that the
+// backing array does not retain references. Use Without, WithoutIndex and WithoutFunc when the input
+// slice is still needed after deletion.
+//
+// slice 包提供了切片相关的泛型工具函数。
+//
+// 除特别说明外，该包中的函数都不会修改传入的切片，并且总是返回一个新分配的切片。例外的是带有 Inplace 后缀的函数
+// 以及 DeleteByIndex、DeleteByItem 和 DeleteByFilterFunc，它们会原地修改传入的切片，返回的切片可能与其共享底层数组。
+// 原地删除后腾出的尾部元素会被置为零值，避免底层数组继续持有引用。如果删除后仍需使用原切片，请使用 Without、
+// WithoutIndex 和 WithoutFunc。
+package slice
@@ -0,0 +1,257 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"github.com/chenmingyong0423/gkit/internal/errors"
+)
+
+// InsertAt inserts items at the given index of the slice.
+// Parameters:
+// - data: the slice to operate on, which is not modified
+// - index: the position to insert at, valid range is [0, len(data)]
+// - items: the items to insert
+//
+// Returns:
+// - a new slice containing the inserted items if the index is valid
+// - a nil slice and an IndexOutOfRange error if the index is out of range
+//
+// InsertAt 在切片的指定索引处插入元素
+// 参数：
+// - data：待操作的切片，不会被修改
+// - index：插入的位置，有效范围为 [0, len(data)]
+// - items：待插入的元素
+//
+// 返回值：
+// - 如果索引值在有效范围内，则返回一个包含插入元素的新切片
+// - 如果索引值不在有效范围内，则返回一个nil切片和一个IndexOutOfRange错误
+func InsertAt[T any](data []T, index int, items ...T) ([]T, error) {
+	length := len(data)
+	if index < 0 || index > length {
+		return nil, errors.NewIndexOutOfRange(length, index)
+	}
+	result := make([]T, 0, length+len(items))
+	result = append(result, data[:index]...)
+	result = append(result, items...)
+	return append(result, data[index:]...), nil
+}
+
+// InsertAtInplace 在切片的指定索引处插入元素，当容量足够时会复用并修改原切片的底层数组
+// 索引值的有效范围为 [0, len(data)]，不在有效范围内时返回一个nil切片和一个IndexOutOfRange错误
+func InsertAtInplace[T any](data []T, index int, items ...T) ([]T, error) {
+	length := len(data)
+	if index < 0 || index > length {
+		return nil, errors.NewIndexOutOfRange(length, index)
+	}
+	n := len(items)
+	data = append(data, items...)
+	copy(data[index+n:], data[index:length])
+	copy(data[index:], items)
+	return data, nil
+}
+
+// ReplaceAt replaces the element at the given index of the slice.
+// Parameters:
+// - data: the slice to operate on, which is not modified
+// - index: the index of the element to replace
+// - item: the new element
+//
+// Returns:
+// - a new slice in which the element at index is replaced if the index is valid
+// - a nil slice and an IndexOutOfRange error if the index is out of range
+//
+// ReplaceAt 替换切片中指定索引处的元素
+// 参数：
+// - data：待操作的切片，不会被修改
+// - index：待替换元素的索引
+// - item：新的元素
+//
+// 返回值：
+// - 如果索引值在有效范围内，则返回一个指定索引处的元素已被替换的新切片
+// - 如果索引值不在有效范围内，则返回一个nil切片和一个IndexOutOfRange错误
+func ReplaceAt[T any](data []T, index int, item T) ([]T, error) {
+	if err := checkIndex(len(data), index); err != nil {
+		return nil, err
+	}
+	result := clone(data)
+	result[index] = item
+	return result, nil
+}
+
+// ReplaceAtInplace 在原始切片上替换指定索引处的元素，索引值不在有效范围内时返回IndexOutOfRange错误
+func ReplaceAtInplace[T any](data []T, index int, item T) error {
+	if err := checkIndex(len(data), index); err != nil {
+		return err
+	}
+	data[index] = item
+	return nil
+}
+
+// Move moves the element at index from to index to, the elements in between are shifted.
+// Parameters:
+// - data: the slice to operate on, which is not modified
+// - from: the index of the element to move
+// - to: the target index of the element
+//
+// Returns:
+// - a new slice in which the element is moved if both indexes are valid
+// - a nil slice and an IndexOutOfRange error if either index is out of range
+//
+// Move 将索引 from 处的元素移动到索引 to 处，中间的元素依次平移
+// 参数：
+// - data：待操作的切片，不会被修改
+// - from：待移动元素的索引
+// - to：元素移动后的索引
+//
+// 返回值：
+// - 如果两个索引值都在有效范围内，则返回一个元素已被移动的新切片
+// - 如果任一索引值不在有效范围内，则返回一个nil切片和一个IndexOutOfRange错误
+func Move[T any](data []T, from, to int) ([]T, error) {
+	if err := checkIndexes(len(data), from, to); err != nil {
+		return nil, err
+	}
+	result := clone(data)
+	moveInplace(result, from, to)
+	return result, nil
+}
+
+// MoveInplace 在原始切片上将索引 from 处的元素移动到索引 to 处，任一索引值不在有效范围内时返回IndexOutOfRange错误
+func MoveInplace[T any](data []T, from, to int) error {
+	if err := checkIndexes(len(data), from, to); err != nil {
+		return err
+	}
+	moveInplace(data, from, to)
+	return nil
+}
+
+// Swap swaps the elements at index i and j.
+// Parameters:
+// - data: the slice to operate on, which is not modified
+// - i: the index of the first element
+// - j: the index of the second element
+//
+// Returns:
+// - a new slice in which the two elements are swapped if both indexes are valid
+// - a nil slice and an IndexOutOfRange error if either index is out of range
+//
+// Swap 交换索引 i 和 j 处的元素
+// 参数：
+// - data：待操作的切片，不会被修改
+// - i：第一个元素的索引
+// - j：第二个元素的索引
+//
+// 返回值：
+// - 如果两个索引值都在有效范围内，则返回一个两个元素已被交换的新切片
+// - 如果任一索引值不在有效范围内，则返回一个nil切片和一个IndexOutOfRange错误
+func Swap[T any](data []T, i, j int) ([]T, error) {
+	if err := checkIndexes(len(data), i, j); err != nil {
+		return nil, err
+	}
+	result := clone(data)
+	result[i], result[j] = result[j], result[i]
+	return result, nil
+}
+
+// SwapInplace 在原始切片上交换索引 i 和 j 处的元素，任一索引值不在有效范围内时返回IndexOutOfRange错误
+func SwapInplace[T any](data []T, i, j int) error {
+	if err := checkIndexes(len(data), i, j); err != nil {
+		return err
+	}
+	data[i], data[j] = data[j], data[i]
+	return nil
+}
+
+// Rotate rotates the slice to the left by k positions, a negative k rotates to the right.
+// Parameters:
+// - data: the slice to operate on, which is not modified
+// - k: the number of positions to rotate, it is taken modulo len(data)
+//
+// Returns:
+// - a new rotated slice
+//
+// Rotate 将切片向左旋转 k 个位置，k 为负数时向右旋转
+// 参数：
+// - data：待操作的切片，不会被修改
+// - k：旋转的位置数，会对 len(data) 取模
+//
+// 返回值：
+// - 旋转后的新切片
+func Rotate[T any](data []T, k int) []T {
+	length := len(data)
+	result := make([]T, 0, length)
+	if length == 0 {
+		return result
+	}
+	k = normalizeRotation(length, k)
+	result = append(result, data[k:]...)
+	return append(result, data[:k]...)
+}
+
+// RotateInplace 在原始切片上将切片向左旋转 k 个位置，k 为负数时向右旋转
+func RotateInplace[T any](data []T, k int) {
+	length := len(data)
+	if length == 0 {
+		return
+	}
+	k = normalizeRotation(length, k)
+	ReverseInplace(data[:k])
+	ReverseInplace(data[k:])
+	ReverseInplace(data)
+}
+
+// normalizeRotation 将旋转的位置数转换到 [0, length) 范围内
+func normalizeRotation(length, k int) int {
+	k %= length
+	if k < 0 {
+		k += length
+	}
+	return k
+}
+
+// moveInplace 将索引 from 处的元素移动到索引 to 处，调用方需保证索引有效
+func moveInplace[T any](data []T, from, to int) {
+	item := data[from]
+	if from < to {
+		copy(data[from:to], data[from+1:to+1])
+	} else {
+		copy(data[to+1:from+1], data[to:from])
+	}
+	data[to] = item
+}
+
+// checkIndex 检查索引是否在 [0, length) 范围内
+func checkIndex(length, index int) error {
+	if index < 0 || index >= length {
+		return errors.NewIndexOutOfRange(length, index)
+	}
+	return nil
+}
+
+// checkIndexes 依次检查多个索引是否都在 [0, length) 范围内
+func checkIndexes(length int, indexes ...int) error {
+	for _, index := range indexes {
+		if err := checkIndex(length, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clone 复制切片，nil 切片会被复制为空切片
+func clone[T any](data []T) []T {
+	result := make([]T, len(data))
+	copy(result, data)
+	return result
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"testing"
+
+	"github.com/chenmingyong0423/gkit/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertAt(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		index   int
+		items   []int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "下标越界，小于 0 的情况",
+			data:    []int{1, 2, 3},
+			index:   -1,
+			items:   []int{4},
+			wantErr: errors.NewIndexOutOfRange(3, -1),
+		},
+		{
+			name:    "下标越界，大于长度的情况",
+			data:    []int{1, 2, 3},
+			index:   4,
+			items:   []int{4},
+			wantErr: errors.NewIndexOutOfRange(3, 4),
+		},
+		{
+			name:  "nil 切片插入",
+			data:  nil,
+			index: 0,
+			items: []int{1, 2},
+			want:  []int{1, 2},
+		},
+		{
+			name:  "在开头插入",
+			data:  []int{1, 2, 3},
+			index: 0,
+			items: []int{4, 5},
+			want:  []int{4, 5, 1, 2, 3},
+		},
+		{
+			name:  "在中间插入",
+			data:  []int{1, 2, 3},
+			index: 1,
+			items: []int{4, 5},
+			want:  []int{1, 4, 5, 2, 3},
+		},
+		{
+			name:  "在末尾插入",
+			data:  []int{1, 2, 3},
+			index: 3,
+			items: []int{4},
+			want:  []int{1, 2, 3, 4},
+		},
+		{
+			name:  "不插入任何元素",
+			data:  []int{1, 2, 3},
+			index: 1,
+			want:  []int{1, 2, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := InsertAt(tt.data, tt.index, tt.items...)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, clone(tt.data))
+
+			data := make([]int, len(tt.data), len(tt.data)+len(tt.items))
+			copy(data, tt.data)
+			res, err = InsertAtInplace(data, tt.index, tt.items...)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
+
+func TestReplaceAt(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		index   int
+		item    int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "下标越界，等于长度的情况",
+			data:    []int{1, 2, 3},
+			index:   3,
+			item:    4,
+			wantErr: errors.NewIndexOutOfRange(3, 3),
+		},
+		{
+			name:    "切片为空的情况",
+			data:    []int{},
+			index:   0,
+			item:    4,
+			wantErr: errors.NewIndexOutOfRange(0, 0),
+		},
+		{
+			name:  "替换中间元素",
+			data:  []int{1, 2, 3},
+			index: 1,
+			item:  4,
+			want:  []int{1, 4, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := ReplaceAt(tt.data, tt.index, tt.item)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, tt.data)
+
+			err = ReplaceAtInplace(tt.data, tt.index, tt.item)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.want, tt.data)
+			}
+		})
+	}
+}
+
+func TestMove(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		from    int
+		to      int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "from 越界",
+			data:    []int{1, 2, 3},
+			from:    3,
+			to:      0,
+			wantErr: errors.NewIndexOutOfRange(3, 3),
+		},
+		{
+			name:    "to 越界",
+			data:    []int{1, 2, 3},
+			from:    0,
+			to:      -1,
+			wantErr: errors.NewIndexOutOfRange(3, -1),
+		},
+		{
+			name: "向后移动",
+			data: []int{0, 1, 2, 3, 4},
+			from: 1,
+			to:   3,
+			want: []int{0, 2, 3, 1, 4},
+		},
+		{
+			name: "向前移动",
+			data: []int{0, 1, 2, 3, 4},
+			from: 4,
+			to:   0,
+			want: []int{4, 0, 1, 2, 3},
+		},
+		{
+			name: "原地不动",
+			data: []int{0, 1, 2},
+			from: 1,
+			to:   1,
+			want: []int{0, 1, 2},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := Move(tt.data, tt.from, tt.to)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, tt.data)
+
+			err = MoveInplace(tt.data, tt.from, tt.to)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.want, tt.data)
+			}
+		})
+	}
+}
+
+func TestSwap(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		i       int
+		j       int
+		want    []int
+		wantErr error
+	}{
+		{
+			name:    "下标越界",
+			data:    []int{1, 2, 3},
+			i:       0,
+			j:       5,
+			wantErr: errors.NewIndexOutOfRange(3, 5),
+		},
+		{
+			name: "交换首尾元素",
+			data: []int{1, 2, 3},
+			i:    0,
+			j:    2,
+			want: []int{3, 2, 1},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			res, err := Swap(tt.data, tt.i, tt.j)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, origin, tt.data)
+
+			err = SwapInplace(tt.data, tt.i, tt.j)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.want, tt.data)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []int
+		k    int
+		want []int
+	}{
+		{
+			name: "nil 切片",
+			data: nil,
+			k:    1,
+			want: []int{},
+		},
+		{
+			name: "向左旋转",
+			data: []int{1, 2, 3, 4, 5},
+			k:    2,
+			want: []int{3, 4, 5, 1, 2},
+		},
+		{
+			name: "向右旋转",
+			data: []int{1, 2, 3, 4, 5},
+			k:    -1,
+			want: []int{5, 1, 2, 3, 4},
+		},
+		{
+			name: "旋转位置数大于长度",
+			data: []int{1, 2, 3},
+			k:    7,
+			want: []int{2, 3, 1},
+		},
+		{
+			name: "旋转位置数等于长度",
+			data: []int{1, 2, 3},
+			k:    3,
+			want: []int{1, 2, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := clone(tt.data)
+			assert.Equal(t, tt.want, Rotate(tt.data, tt.k))
+			assert.Equal(t, origin, clone(tt.data))
+
+			RotateInplace(tt.data, tt.k)
+			assert.Equal(t, tt.want, clone(tt.data))
+		})
+	}
+}
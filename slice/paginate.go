@@ -0,0 +1,207 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+
+	"github.com/chenmingyong0423/gkit/internal/errors"
+)
+
+// Page 是分页查询的结果，页码从 1 开始
+// Page is the result of offset-based pagination, the page number starts from 1.
+type Page[T any] struct {
+	// Items 当前页的元素
+	Items []T
+	// Page 当前页码
+	Page int
+	// Size 每页的元素个数
+	Size int
+	// Total 元素总数
+	Total int
+	// Pages 总页数
+	Pages int
+	// HasNext 是否存在下一页
+	HasNext bool
+	// HasPrev 是否存在上一页
+	HasPrev bool
+}
+
+// Paginate returns the given page of the slice.
+// Parameters:
+// - data: the slice to paginate
+// - page: the page number, starting from 1. The first page is always valid, even if data is empty
+// - size: the number of elements per page, must be greater than 0
+//
+// Returns:
+// - the page containing a copy of the elements and the pagination metadata
+// - an InvalidPageSize error if size <= 0, or a PageOutOfRange error if page is out of range
+//
+// Paginate 返回切片中指定页的数据
+// 参数：
+// - data: 要分页的切片
+// - page: 页码，从 1 开始，即使 data 为空，第一页也总是有效的
+// - size: 每页的元素个数，必须大于 0
+//
+// 返回值：
+// - 包含元素副本和分页信息的 Page
+// - 如果 size <= 0，则返回 InvalidPageSize 错误；如果页码超出范围，则返回 PageOutOfRange 错误
+func Paginate[T any](data []T, page, size int) (Page[T], error) {
+	if size <= 0 {
+		return Page[T]{}, errors.NewInvalidPageSize(size)
+	}
+	total := len(data)
+	pages := (total + size - 1) / size
+	if page < 1 || (page > pages && page != 1) {
+		return Page[T]{}, errors.NewPageOutOfRange(pages, page)
+	}
+	start := min((page-1)*size, total)
+	end := min(start+size, total)
+	return Page[T]{
+		Items:   clone(data[start:end]),
+		Page:    page,
+		Size:    size,
+		Total:   total,
+		Pages:   pages,
+		HasNext: page < pages,
+		HasPrev: page > 1,
+	}, nil
+}
+
+// CursorPage 是游标分页查询的结果
+// CursorPage is the result of cursor-based pagination.
+type CursorPage[T any] struct {
+	// Items 当前页的元素
+	Items []T
+	// NextCursor 获取下一页所需的游标，不存在下一页时为空字符串
+	NextCursor string
+	// HasNext 是否存在下一页
+	HasNext bool
+}
+
+// CursorPaginator 基于 key 的游标分页器，游标是对当前页最后一个元素的 key 进行编码得到的不透明字符串
+// CursorPaginator paginates slices by key, the cursor is an opaque string encoded from the key of the last element of a page.
+type CursorPaginator[T any, K cmp.Ordered] struct {
+	keyFn func(T) K
+}
+
+// NewCursorPaginator creates a cursor paginator.
+// Parameters:
+// - keyFn: the function used to extract the key of an element, the keys must be unique
+//
+// Returns:
+// - a new CursorPaginator
+//
+// NewCursorPaginator 创建一个游标分页器
+// 参数：
+// - keyFn: 用于提取元素 key 的函数，key 必须唯一
+//
+// 返回值：
+// - 一个新的 CursorPaginator
+func NewCursorPaginator[T any, K cmp.Ordered](keyFn func(T) K) *CursorPaginator[T, K] {
+	return &CursorPaginator[T, K]{
+		keyFn: keyFn,
+	}
+}
+
+// Paginate returns the page after the given cursor.
+// Parameters:
+// - data: the slice to paginate, which must be sorted in ascending order of the key
+// - cursor: the cursor returned by the previous page, an empty cursor means the first page
+// - size: the number of elements per page, must be greater than 0
+//
+// Returns:
+// - the page containing a copy of the elements whose keys are greater than the cursor
+// - an InvalidPageSize error if size <= 0, or an InvalidCursor error if the cursor can not be decoded
+// - an InvalidCursorKey error if the key of the last element of the page is NaN or ±Inf and can not be encoded
+//
+// Paginate 返回游标之后的一页数据
+// 参数：
+// - data: 要分页的切片，必须按 key 升序排列
+// - cursor: 上一页返回的游标，为空时表示第一页
+// - size: 每页的元素个数，必须大于 0
+//
+// 返回值：
+// - 包含 key 大于游标的元素副本的 CursorPage
+// - 如果 size <= 0，则返回 InvalidPageSize 错误；如果游标无法解码，则返回 InvalidCursor 错误
+// - 如果当前页最后一个元素的 key 为 NaN 或 ±Inf 而无法编码，则返回 InvalidCursorKey 错误
+func (p *CursorPaginator[T, K]) Paginate(data []T, cursor string, size int) (CursorPage[T], error) {
+	if size <= 0 {
+		return CursorPage[T]{}, errors.NewInvalidPageSize(size)
+	}
+	start := 0
+	if cursor != "" {
+		key, err := p.decode(cursor)
+		if err != nil {
+			return CursorPage[T]{}, err
+		}
+		start = lowerBoundFunc(data, func(item T) bool {
+			return p.keyFn(item) <= key
+		})
+	}
+	end := min(start+size, len(data))
+	result := CursorPage[T]{
+		Items:   clone(data[start:end]),
+		HasNext: end < len(data),
+	}
+	if result.HasNext {
+		next, err := p.encode(p.keyFn(data[end-1]))
+		if err != nil {
+			return CursorPage[T]{}, err
+		}
+		result.NextCursor = next
+	}
+	return result, nil
+}
+
+// stringCursorPrefix 是字符串 key 游标的前缀，保证空字符串 key 也会得到非空的游标
+const stringCursorPrefix = 's'
+
+// encode 将 key 编码为不透明的游标
+// 字符串 key 直接编码其原始字节，因为 json 序列化会将非法的 UTF-8 替换为 U+FFFD，导致下一页重复或遗漏元素
+// 除了浮点数的 NaN 和 ±Inf，cmp.Ordered 类型的值都可以被 json 序列化，这两种值返回 InvalidCursorKey 错误
+func (p *CursorPaginator[T, K]) encode(key K) (string, error) {
+	if v := reflect.ValueOf(key); v.Kind() == reflect.String {
+		return base64.RawURLEncoding.EncodeToString(append([]byte{stringCursorPrefix}, v.String()...)), nil
+	}
+	bs, err := json.Marshal(key)
+	if err != nil {
+		return "", errors.NewInvalidCursorKey(key)
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
+
+// decode 将游标解码为 key
+func (p *CursorPaginator[T, K]) decode(cursor string) (K, error) {
+	var key K
+	bs, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return key, errors.NewInvalidCursor(cursor)
+	}
+	if v := reflect.ValueOf(&key).Elem(); v.Kind() == reflect.String {
+		if len(bs) == 0 || bs[0] != stringCursorPrefix {
+			return key, errors.NewInvalidCursor(cursor)
+		}
+		v.SetString(string(bs[1:]))
+		return key, nil
+	}
+	if err = json.Unmarshal(bs, &key); err != nil {
+		return key, errors.NewInvalidCursor(cursor)
+	}
+	return key, nil
+}
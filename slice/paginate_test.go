@@ -0,0 +1,234 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chenmingyong0423/gkit/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		page    int
+		size    int
+		want    Page[int]
+		wantErr error
+	}{
+		{
+			name:    "size 小于等于 0",
+			data:    []int{1, 2, 3},
+			page:    1,
+			size:    0,
+			wantErr: errors.NewInvalidPageSize(0),
+		},
+		{
+			name:    "page 小于 1",
+			data:    []int{1, 2, 3},
+			page:    0,
+			size:    2,
+			wantErr: errors.NewPageOutOfRange(2, 0),
+		},
+		{
+			name:    "page 大于总页数",
+			data:    []int{1, 2, 3},
+			page:    3,
+			size:    2,
+			wantErr: errors.NewPageOutOfRange(2, 3),
+		},
+		{
+			name: "空切片的第一页",
+			data: nil,
+			page: 1,
+			size: 2,
+			want: Page[int]{Items: []int{}, Page: 1, Size: 2},
+		},
+		{
+			name: "第一页",
+			data: []int{1, 2, 3, 4, 5},
+			page: 1,
+			size: 2,
+			want: Page[int]{Items: []int{1, 2}, Page: 1, Size: 2, Total: 5, Pages: 3, HasNext: true},
+		},
+		{
+			name: "中间页",
+			data: []int{1, 2, 3, 4, 5},
+			page: 2,
+			size: 2,
+			want: Page[int]{Items: []int{3, 4}, Page: 2, Size: 2, Total: 5, Pages: 3, HasNext: true, HasPrev: true},
+		},
+		{
+			name: "最后一页不满",
+			data: []int{1, 2, 3, 4, 5},
+			page: 3,
+			size: 2,
+			want: Page[int]{Items: []int{5}, Page: 3, Size: 2, Total: 5, Pages: 3, HasPrev: true},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Paginate(tt.data, tt.page, tt.size)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
+
+func TestCursorPaginator_Paginate(t *testing.T) {
+	type User struct {
+		Id   int64
+		Name string
+	}
+	users := []User{{Id: 1, Name: "a"}, {Id: 3, Name: "b"}, {Id: 5, Name: "c"}, {Id: 7, Name: "d"}, {Id: 9, Name: "e"}}
+	paginator := NewCursorPaginator(func(u User) int64 {
+		return u.Id
+	})
+
+	t.Run("size 小于等于 0", func(t *testing.T) {
+		_, err := paginator.Paginate(users, "", -1)
+		assert.Equal(t, errors.NewInvalidPageSize(-1), err)
+	})
+
+	t.Run("非法游标", func(t *testing.T) {
+		_, err := paginator.Paginate(users, "!!!", 2)
+		assert.Equal(t, errors.NewInvalidCursor("!!!"), err)
+
+		_, err = paginator.Paginate(users, "YWJj", 2)
+		assert.Equal(t, errors.NewInvalidCursor("YWJj"), err)
+	})
+
+	t.Run("遍历所有页", func(t *testing.T) {
+		var (
+			cursor string
+			got    []User
+			pages  int
+		)
+		for {
+			page, err := paginator.Paginate(users, cursor, 2)
+			require.NoError(t, err)
+			got = append(got, page.Items...)
+			pages++
+			if !page.HasNext {
+				assert.Empty(t, page.NextCursor)
+				break
+			}
+			cursor = page.NextCursor
+		}
+		assert.Equal(t, users, got)
+		assert.Equal(t, 3, pages)
+	})
+
+	t.Run("游标对应的元素已被删除", func(t *testing.T) {
+		page, err := paginator.Paginate(users, "", 2)
+		require.NoError(t, err)
+		// 删除游标对应的元素 3 后，下一页仍然从 5 开始
+		remaining := []User{users[0], users[2], users[3], users[4]}
+		page, err = paginator.Paginate(remaining, page.NextCursor, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []User{users[2], users[3]}, page.Items)
+		assert.True(t, page.HasNext)
+	})
+}
+
+func TestCursorPaginator_InvalidKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []float64
+		want error
+	}{
+		{
+			name: "NaN",
+			data: []float64{math.NaN(), 1},
+			want: errors.NewInvalidCursorKey(math.NaN()),
+		},
+		{
+			name: "负无穷",
+			data: []float64{math.Inf(-1), 1},
+			want: errors.NewInvalidCursorKey(math.Inf(-1)),
+		},
+		{
+			name: "正无穷不是当前页的最后一个元素",
+			data: []float64{1, math.Inf(1)},
+		},
+	}
+	paginator := NewCursorPaginator(func(f float64) float64 {
+		return f
+	})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// 无法编码的 key 返回错误，而不是返回空游标
+			page, err := paginator.Paginate(tt.data, "", 1)
+			assert.Equal(t, tt.want, err)
+			if err == nil {
+				assert.NotEmpty(t, page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestCursorPaginator_StringKey(t *testing.T) {
+	type Name string
+	testCases := []struct {
+		name string
+		data []Name
+	}{
+		{
+			name: "合法的 UTF-8",
+			data: []Name{"a", "b", "中", "文"},
+		},
+		{
+			// json 序列化会将它们都替换为 U+FFFD
+			name: "非法的 UTF-8",
+			data: []Name{"\x80", "\x81", "\xfe", "\xff"},
+		},
+		{
+			// 空字符串 key 的游标不能为空，否则会被当作第一页
+			name: "空字符串",
+			data: []Name{"", "a", "b"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator := NewCursorPaginator(func(n Name) Name {
+				return n
+			})
+			var (
+				cursor string
+				got    []Name
+			)
+			for {
+				page, err := paginator.Paginate(tt.data, cursor, 1)
+				require.NoError(t, err)
+				got = append(got, page.Items...)
+				if !page.HasNext {
+					break
+				}
+				require.NotEmpty(t, page.NextCursor)
+				cursor = page.NextCursor
+			}
+			assert.Equal(t, tt.data, got)
+
+			// 缺少前缀的游标是非法的
+			_, err := paginator.Paginate(tt.data, "YWJj", 1)
+			assert.Equal(t, errors.NewInvalidCursor("YWJj"), err)
+		})
+	}
+}
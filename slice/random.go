@@ -0,0 +1,299 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/chenmingyong0423/gkit/internal/errors"
+)
+
+// Shuffle returns a new slice containing the elements of data in random order, the original slice is not modified.
+// Parameters:
+// - data: the slice to shuffle
+// - r: the random source, the default source of math/rand is used if r is nil
+//
+// Returns:
+// - a new shuffled slice
+//
+// Shuffle 返回一个元素顺序被随机打乱的新切片，原切片不会被修改
+// 参数：
+// - data: 要打乱的切片
+// - r: 随机数源，如果为 nil，则使用 math/rand 的默认随机数源
+//
+// 返回值：
+// - 打乱后的新切片
+func Shuffle[T any](data []T, r *rand.Rand) []T {
+	res := make([]T, len(data))
+	copy(res, data)
+	ShuffleInplace(res, r)
+	return res
+}
+
+// ShuffleInplace shuffles the original slice
+// Parameters:
+// - data: the slice to shuffle
+// - r: the random source, the default source of math/rand is used if r is nil
+//
+// ShuffleInplace 在原始切片上进行随机打乱
+// 参数：
+// - data: 要打乱的切片
+// - r: 随机数源，如果为 nil，则使用 math/rand 的默认随机数源
+func ShuffleInplace[T any](data []T, r *rand.Rand) {
+	for i := len(data) - 1; i > 0; i-- {
+		j := randIntn(r, i+1)
+		data[i], data[j] = data[j], data[i]
+	}
+}
+
+// Sample randomly selects k distinct positions of data without replacement.
+// Parameters:
+// - data: the slice to sample from
+// - k: the number of elements to select
+// - r: the random source, the default source of math/rand is used if r is nil
+//
+// Returns:
+// - a new slice containing k elements of data in random order
+// - a SampleSizeOutOfRange error if k is negative or greater than len(data)
+//
+// Sample 从切片中不放回地随机选取 k 个位置上的元素
+// 参数：
+// - data: 被抽样的切片
+// - k: 要选取的元素个数
+// - r: 随机数源，如果为 nil，则使用 math/rand 的默认随机数源
+//
+// 返回值：
+// - 一个以随机顺序包含 data 中 k 个元素的新切片
+// - 如果 k 为负数或大于 len(data)，则返回 SampleSizeOutOfRange 错误
+func Sample[T any](data []T, k int, r *rand.Rand) ([]T, error) {
+	length := len(data)
+	if k < 0 || k > length {
+		return nil, errors.NewSampleSizeOutOfRange(length, k)
+	}
+	// 部分 Fisher–Yates 洗牌，只需要打乱前 k 个位置
+	tmp := make([]T, length)
+	copy(tmp, data)
+	for i := 0; i < k; i++ {
+		j := i + randIntn(r, length-i)
+		tmp[i], tmp[j] = tmp[j], tmp[i]
+	}
+	res := make([]T, k)
+	copy(res, tmp[:k])
+	return res, nil
+}
+
+// Choice randomly selects an element from data.
+// Parameters:
+// - data: the slice to choose from
+// - r: the random source, the default source of math/rand is used if r is nil
+//
+// Returns:
+// - the selected element
+// - an EmptySlice error if data is empty
+//
+// Choice 从切片中随机选取一个元素
+// 参数：
+// - data: 被选取的切片
+// - r: 随机数源，如果为 nil，则使用 math/rand 的默认随机数源
+//
+// 返回值：
+// - 被选中的元素
+// - 如果切片为空，则返回 EmptySlice 错误
+func Choice[T any](data []T, r *rand.Rand) (T, error) {
+	if len(data) == 0 {
+		var zero T
+		return zero, errors.NewEmptySlice()
+	}
+	return data[randIntn(r, len(data))], nil
+}
+
+// WeightedChoice randomly selects an element from items, the probability of each element is proportional to its weight.
+// It builds an alias table on each call, use NewWeightedChooser when drawing repeatedly from the same items.
+// Parameters:
+// - items: the elements to choose from
+// - weights: the weight of each element, must be non-negative and have the same length as items
+// - r: the random source, the default source of math/rand is used if r is nil
+//
+// Returns:
+// - the selected element
+// - an error if the items or weights are invalid
+//
+// WeightedChoice 按权重从 items 中随机选取一个元素，每个元素被选中的概率与其权重成正比
+// 每次调用都会构建别名表，如需在同一组元素上多次抽取，请使用 NewWeightedChooser
+// 参数：
+// - items: 被选取的元素
+// - weights: 每个元素的权重，必须为非负数且长度与 items 相同
+// - r: 随机数源，如果为 nil，则使用 math/rand 的默认随机数源
+//
+// 返回值：
+// - 被选中的元素
+// - 如果 items 或 weights 不合法，则返回错误
+func WeightedChoice[T any](items []T, weights []float64, r *rand.Rand) (T, error) {
+	chooser, err := NewWeightedChooser(items, weights)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return chooser.Choose(r), nil
+}
+
+// WeightedChooser 使用别名表（Vose's alias method）实现 O(1) 时间复杂度的加权随机选取
+// WeightedChooser draws weighted random elements in O(1) time using the alias method.
+type WeightedChooser[T any] struct {
+	items []T
+	prob  []float64
+	alias []int
+}
+
+// NewWeightedChooser builds an alias table for the given items and weights in O(n) time.
+// Parameters:
+// - items: the elements to choose from
+// - weights: the weight of each element, must be non-negative and have the same length as items
+//
+// Returns:
+// - a new WeightedChooser
+// - an error if items is empty, the lengths do not match, a weight is negative or not finite, or the total weight is not positive
+//
+// NewWeightedChooser 以 O(n) 的时间复杂度为给定的元素和权重构建别名表
+// 参数：
+// - items: 被选取的元素
+// - weights: 每个元素的权重，必须为非负数且长度与 items 相同
+//
+// 返回值：
+// - 一个新的 WeightedChooser
+// - 如果 items 为空、长度不一致、存在负数或非有限的权重，或者总权重不为正数，则返回错误
+func NewWeightedChooser[T any](items []T, weights []float64) (*WeightedChooser[T], error) {
+	n := len(items)
+	if n == 0 {
+		return nil, errors.NewEmptySlice()
+	}
+	if n != len(weights) {
+		return nil, errors.NewLengthMismatch(n, len(weights))
+	}
+	total := 0.0
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, errors.NewInvalidWeight(i, w)
+		}
+		total += w
+	}
+	if total <= 0 || math.IsInf(total, 0) {
+		return nil, errors.NewNonPositiveTotalWeight(total)
+	}
+
+	prob := make([]float64, n)
+	alias := make([]int, n)
+	scaled := make([]float64, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		prob[s], alias[s] = scaled[s], l
+		scaled[l] = scaled[l] + scaled[s] - 1
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+	// 剩余的元素由于浮点误差，其概率都视为 1
+	for _, i := range large {
+		prob[i] = 1
+	}
+	for _, i := range small {
+		prob[i] = 1
+	}
+
+	copied := make([]T, n)
+	copy(copied, items)
+	return &WeightedChooser[T]{
+		items: copied,
+		prob:  prob,
+		alias: alias,
+	}, nil
+}
+
+// Choose 按权重随机选取一个元素，r 为 nil 时使用 math/rand 的默认随机数源
+// Choose draws a weighted random element, the default source of math/rand is used if r is nil.
+func (c *WeightedChooser[T]) Choose(r *rand.Rand) T {
+	i := randIntn(r, len(c.items))
+	if randFloat64(r) < c.prob[i] {
+		return c.items[i]
+	}
+	return c.items[c.alias[i]]
+}
+
+// ReservoirSample selects k elements uniformly at random from a stream of unknown length using reservoir sampling.
+// Parameters:
+// - next: the iterator of the stream, which returns false when the stream is exhausted
+// - k: the number of elements to select
+// - r: the random source, the default source of math/rand is used if r is nil
+//
+// Returns:
+// - a new slice containing at most k elements, fewer if the stream has less than k elements
+//
+// ReservoirSample 使用蓄水池抽样，从长度未知的数据流中等概率地随机选取 k 个元素
+// 参数：
+// - next: 数据流的迭代器，数据流耗尽时返回 false
+// - k: 要选取的元素个数
+// - r: 随机数源，如果为 nil，则使用 math/rand 的默认随机数源
+//
+// 返回值：
+// - 一个最多包含 k 个元素的新切片，如果数据流中的元素少于 k 个，则返回所有元素
+func ReservoirSample[T any](next func() (T, bool), k int, r *rand.Rand) []T {
+	if k <= 0 {
+		return []T{}
+	}
+	res := make([]T, 0, k)
+	for i := 0; ; i++ {
+		item, ok := next()
+		if !ok {
+			return res
+		}
+		if i < k {
+			res = append(res, item)
+			continue
+		}
+		if j := randIntn(r, i+1); j < k {
+			res[j] = item
+		}
+	}
+}
+
+// randIntn 在 r 为 nil 时使用 math/rand 的默认随机数源
+func randIntn(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	return r.Intn(n)
+}
+
+// randFloat64 在 r 为 nil 时使用 math/rand 的默认随机数源
+func randFloat64(r *rand.Rand) float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+	return r.Float64()
+}
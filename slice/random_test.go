@@ -0,0 +1,245 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/chenmingyong0423/gkit/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShuffle(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []int
+	}{
+		{
+			name: "nil slice",
+			data: nil,
+		},
+		{
+			name: "单个元素",
+			data: []int{1},
+		},
+		{
+			name: "多个元素",
+			data: []int{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := append([]int(nil), tt.data...)
+			res := Shuffle(tt.data, rand.New(rand.NewSource(1)))
+			assert.ElementsMatch(t, tt.data, res)
+			assert.Equal(t, origin, tt.data)
+			// 相同的随机数种子得到相同的结果
+			assert.Equal(t, res, Shuffle(tt.data, rand.New(rand.NewSource(1))))
+		})
+	}
+}
+
+func TestShuffleInplace(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	ShuffleInplace(data, nil)
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, data)
+}
+
+func TestSample(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		k       int
+		wantLen int
+		wantErr error
+	}{
+		{
+			name:    "k 小于 0",
+			data:    []int{1, 2, 3},
+			k:       -1,
+			wantErr: errors.NewSampleSizeOutOfRange(3, -1),
+		},
+		{
+			name:    "k 大于切片长度",
+			data:    []int{1, 2, 3},
+			k:       4,
+			wantErr: errors.NewSampleSizeOutOfRange(3, 4),
+		},
+		{
+			name:    "k 等于 0",
+			data:    []int{1, 2, 3},
+			k:       0,
+			wantLen: 0,
+		},
+		{
+			name:    "k 小于切片长度",
+			data:    []int{1, 2, 3, 4, 5},
+			k:       3,
+			wantLen: 3,
+		},
+		{
+			name:    "k 等于切片长度",
+			data:    []int{1, 2, 3, 4, 5},
+			k:       5,
+			wantLen: 5,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := append([]int(nil), tt.data...)
+			res, err := Sample(tt.data, tt.k, rand.New(rand.NewSource(1)))
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Len(t, res, tt.wantLen)
+			assert.Len(t, Deduplicate(res), tt.wantLen)
+			assert.Subset(t, tt.data, res)
+			assert.Equal(t, origin, tt.data)
+		})
+	}
+}
+
+func TestChoice(t *testing.T) {
+	_, err := Choice([]int{}, nil)
+	assert.Equal(t, errors.NewEmptySlice(), err)
+
+	data := []int{1, 2, 3}
+	res, err := Choice(data, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	assert.Contains(t, data, res)
+}
+
+func TestWeightedChoice(t *testing.T) {
+	testCases := []struct {
+		name    string
+		items   []string
+		weights []float64
+		wantErr error
+	}{
+		{
+			name:    "空切片",
+			items:   []string{},
+			weights: []float64{},
+			wantErr: errors.NewEmptySlice(),
+		},
+		{
+			name:    "长度不一致",
+			items:   []string{"a", "b"},
+			weights: []float64{1},
+			wantErr: errors.NewLengthMismatch(2, 1),
+		},
+		{
+			name:    "负数权重",
+			items:   []string{"a", "b"},
+			weights: []float64{1, -1},
+			wantErr: errors.NewInvalidWeight(1, -1),
+		},
+		{
+			name:    "NaN 权重",
+			items:   []string{"a"},
+			weights: []float64{math.NaN()},
+			wantErr: errors.NewInvalidWeight(0, math.NaN()),
+		},
+		{
+			name:    "总权重为 0",
+			items:   []string{"a", "b"},
+			weights: []float64{0, 0},
+			wantErr: errors.NewNonPositiveTotalWeight(0),
+		},
+		{
+			name:    "正常选取",
+			items:   []string{"a", "b"},
+			weights: []float64{0, 1},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := WeightedChoice(tt.items, tt.weights, rand.New(rand.NewSource(1)))
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "b", res)
+		})
+	}
+}
+
+func TestWeightedChooser_Choose(t *testing.T) {
+	chooser, err := NewWeightedChooser([]string{"a", "b", "c", "d"}, []float64{1, 2, 7, 0})
+	require.NoError(t, err)
+
+	r := rand.New(rand.NewSource(1))
+	const total = 100000
+	counts := make(map[string]int)
+	for i := 0; i < total; i++ {
+		counts[chooser.Choose(r)]++
+	}
+	assert.Zero(t, counts["d"])
+	assert.InDelta(t, 0.1, float64(counts["a"])/total, 0.01)
+	assert.InDelta(t, 0.2, float64(counts["b"])/total, 0.01)
+	assert.InDelta(t, 0.7, float64(counts["c"])/total, 0.01)
+}
+
+func TestReservoirSample(t *testing.T) {
+	iterator := func(data []int) func() (int, bool) {
+		i := 0
+		return func() (int, bool) {
+			if i >= len(data) {
+				return 0, false
+			}
+			i++
+			return data[i-1], true
+		}
+	}
+	testCases := []struct {
+		name    string
+		data    []int
+		k       int
+		wantLen int
+	}{
+		{
+			name:    "k 小于等于 0",
+			data:    []int{1, 2, 3},
+			k:       0,
+			wantLen: 0,
+		},
+		{
+			name:    "数据流元素少于 k",
+			data:    []int{1, 2},
+			k:       3,
+			wantLen: 2,
+		},
+		{
+			name:    "数据流元素多于 k",
+			data:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			k:       3,
+			wantLen: 3,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			res := ReservoirSample(iterator(tt.data), tt.k, rand.New(rand.NewSource(1)))
+			assert.Len(t, res, tt.wantLen)
+			assert.Len(t, Deduplicate(res), tt.wantLen)
+			assert.Subset(t, tt.data, res)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import "cmp"
+
+// BinarySearch searches for the target element in a slice sorted in ascending order.
+// Parameters:
+// - data: the slice to search, which must be sorted in ascending order
+// - target: the element to search for
+//
+// Returns:
+// - the index of the first occurrence of target if it is found, otherwise the position where target would be inserted
+// - true if target is found; false otherwise
+//
+// BinarySearch 在升序排列的切片中查找目标元素
+// 参数：
+// - data: 要搜索的切片，必须按升序排列
+// - target: 要查找的元素
+//
+// 返回值：
+// - 如果找到目标元素，则返回其第一次出现的索引，否则返回目标元素应插入的位置
+// - 如果找到目标元素，则为 true；否则为 false
+func BinarySearch[T cmp.Ordered](data []T, target T) (int, bool) {
+	idx := LowerBound(data, target)
+	return idx, idx < len(data) && data[idx] == target
+}
+
+// BinarySearchBy searches for the element whose key equals target in a slice sorted in ascending order of the key.
+// Parameters:
+// - data: the slice to search, which must be sorted in ascending order of the key returned by keyFn
+// - target: the key to search for
+// - keyFn: the function used to extract the key of an element
+//
+// Returns:
+// - the index of the first element whose key equals target if it is found, otherwise the position where target would be inserted
+// - true if target is found; false otherwise
+//
+// BinarySearchBy 在按 key 升序排列的切片中查找 key 等于 target 的元素
+// 参数：
+// - data: 要搜索的切片，必须按 keyFn 返回的 key 升序排列
+// - target: 要查找的 key
+// - keyFn: 用于提取元素 key 的函数
+//
+// 返回值：
+// - 如果找到目标元素，则返回第一个 key 等于 target 的元素的索引，否则返回目标元素应插入的位置
+// - 如果找到目标元素，则为 true；否则为 false
+func BinarySearchBy[T any, K cmp.Ordered](data []T, target K, keyFn func(T) K) (int, bool) {
+	idx := lowerBoundFunc(data, func(item T) bool {
+		return keyFn(item) < target
+	})
+	return idx, idx < len(data) && keyFn(data[idx]) == target
+}
+
+// LowerBound returns the index of the first element that is not less than target in a slice sorted in ascending order.
+// Parameters:
+// - data: the slice to search, which must be sorted in ascending order
+// - target: the element to compare with
+//
+// Returns:
+// - the index of the first element >= target, or len(data) if there is no such element
+//
+// LowerBound 返回升序切片中第一个不小于 target 的元素的索引
+// 参数：
+// - data: 要搜索的切片，必须按升序排列
+// - target: 用于比较的元素
+//
+// 返回值：
+// - 第一个 >= target 的元素的索引，如果不存在这样的元素则返回 len(data)
+func LowerBound[T cmp.Ordered](data []T, target T) int {
+	return lowerBoundFunc(data, func(item T) bool {
+		return item < target
+	})
+}
+
+// UpperBound returns the index of the first element that is greater than target in a slice sorted in ascending order.
+// Parameters:
+// - data: the slice to search, which must be sorted in ascending order
+// - target: the element to compare with
+//
+// Returns:
+// - the index of the first element > target, or len(data) if there is no such element
+//
+// UpperBound 返回升序切片中第一个大于 target 的元素的索引
+// 参数：
+// - data: 要搜索的切片，必须按升序排列
+// - target: 用于比较的元素
+//
+// 返回值：
+// - 第一个 > target 的元素的索引，如果不存在这样的元素则返回 len(data)
+func UpperBound[T cmp.Ordered](data []T, target T) int {
+	return lowerBoundFunc(data, func(item T) bool {
+		return item <= target
+	})
+}
+
+// lowerBoundFunc 返回第一个使 before 返回 false 的元素的索引，要求切片中使 before 返回 true 的元素都位于前面
+func lowerBoundFunc[T any](data []T, before func(item T) bool) int {
+	low, high := 0, len(data)
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if before(data[mid]) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinarySearch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      []int
+		target    int
+		wantIdx   int
+		wantFound bool
+	}{
+		{
+			name:      "nil slice",
+			data:      nil,
+			target:    1,
+			wantIdx:   0,
+			wantFound: false,
+		},
+		{
+			name:      "找到元素",
+			data:      []int{1, 3, 5, 7},
+			target:    5,
+			wantIdx:   2,
+			wantFound: true,
+		},
+		{
+			name:      "找到重复元素的第一个位置",
+			data:      []int{1, 3, 3, 3, 7},
+			target:    3,
+			wantIdx:   1,
+			wantFound: true,
+		},
+		{
+			name:      "元素不存在，返回插入位置",
+			data:      []int{1, 3, 5, 7},
+			target:    4,
+			wantIdx:   2,
+			wantFound: false,
+		},
+		{
+			name:      "元素大于所有元素",
+			data:      []int{1, 3, 5, 7},
+			target:    8,
+			wantIdx:   4,
+			wantFound: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, found := BinarySearch(tt.data, tt.target)
+			assert.Equal(t, tt.wantIdx, idx)
+			assert.Equal(t, tt.wantFound, found)
+		})
+	}
+}
+
+func TestBinarySearchBy(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+	users := []User{{Id: 1, Name: "a"}, {Id: 3, Name: "b"}, {Id: 5, Name: "c"}}
+	testCases := []struct {
+		name      string
+		data      []User
+		target    int
+		wantIdx   int
+		wantFound bool
+	}{
+		{
+			name:      "空切片",
+			data:      []User{},
+			target:    1,
+			wantIdx:   0,
+			wantFound: false,
+		},
+		{
+			name:      "找到元素",
+			data:      users,
+			target:    3,
+			wantIdx:   1,
+			wantFound: true,
+		},
+		{
+			name:      "元素不存在",
+			data:      users,
+			target:    2,
+			wantIdx:   1,
+			wantFound: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, found := BinarySearchBy(tt.data, tt.target, func(u User) int {
+				return u.Id
+			})
+			assert.Equal(t, tt.wantIdx, idx)
+			assert.Equal(t, tt.wantFound, found)
+		})
+	}
+}
+
+func TestLowerBoundAndUpperBound(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      []int
+		target    int
+		wantLower int
+		wantUpper int
+	}{
+		{
+			name:      "nil slice",
+			data:      nil,
+			target:    1,
+			wantLower: 0,
+			wantUpper: 0,
+		},
+		{
+			name:      "目标元素重复",
+			data:      []int{1, 2, 2, 2, 3},
+			target:    2,
+			wantLower: 1,
+			wantUpper: 4,
+		},
+		{
+			name:      "目标元素小于所有元素",
+			data:      []int{1, 2, 3},
+			target:    0,
+			wantLower: 0,
+			wantUpper: 0,
+		},
+		{
+			name:      "目标元素大于所有元素",
+			data:      []int{1, 2, 3},
+			target:    4,
+			wantLower: 3,
+			wantUpper: 3,
+		},
+		{
+			name:      "目标元素不存在",
+			data:      []int{1, 3, 5},
+			target:    4,
+			wantLower: 2,
+			wantUpper: 2,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLower, LowerBound(tt.data, tt.target))
+			assert.Equal(t, tt.wantUpper, UpperBound(tt.data, tt.target))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import "cmp"
+
+// InsertSorted inserts the item into a slice sorted in ascending order and keeps it sorted.
+// Parameters:
+// - data: the slice sorted in ascending order
+// - item: the item to insert, which is placed after the existing equal elements
+//
+// Returns:
+// - a new sorted slice containing the item, the original slice is not modified
+//
+// InsertSorted 将元素插入升序切片中，并保持切片有序
+// 参数：
+// - data: 升序排列的切片
+// - item: 要插入的元素，会被放在已有的相等元素之后
+//
+// 返回值：
+// - 一个包含该元素的新的有序切片，原切片不会被修改
+func InsertSorted[T cmp.Ordered](data []T, item T) []T {
+	idx := UpperBound(data, item)
+	result := make([]T, 0, len(data)+1)
+	result = append(result, data[:idx]...)
+	result = append(result, item)
+	return append(result, data[idx:]...)
+}
+
+// MergeSorted merges two slices sorted in ascending order into a new sorted slice, duplicate elements are kept.
+// This function has a time complexity of O(n+m).
+// Parameters:
+// - a: the first slice sorted in ascending order
+// - b: the second slice sorted in ascending order
+//
+// Returns:
+// - a new slice containing all the elements of a and b in ascending order
+//
+// MergeSorted 将两个升序切片合并成一个新的有序切片，重复元素会被保留，时间复杂度为 O(n+m)
+// 参数：
+// - a: 第一个升序切片
+// - b: 第二个升序切片
+//
+// 返回值：
+// - 一个按升序包含 a 和 b 所有元素的新切片
+func MergeSorted[T cmp.Ordered](a []T, b []T) []T {
+	result := make([]T, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			result = append(result, b[j])
+			j++
+		} else {
+			result = append(result, a[i])
+			i++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+// IntersectionSetSorted 从给定的两个升序切片中取交集，时间复杂度为 O(n+m)
+// 返回的新切片已去重且保持升序
+func IntersectionSetSorted[T cmp.Ordered](s1 []T, s2 []T) []T {
+	result := make([]T, 0, min(len(s1), len(s2)))
+	i, j := 0, 0
+	for i < len(s1) && j < len(s2) {
+		switch {
+		case s1[i] < s2[j]:
+			i++
+		case s2[j] < s1[i]:
+			j++
+		default:
+			result = appendIfNotLast(result, s1[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// UnionSorted 计算两个升序切片的并集，时间复杂度为 O(n+m)
+// 返回的新切片已去重且保持升序
+func UnionSorted[T cmp.Ordered](s1 []T, s2 []T) []T {
+	result := make([]T, 0, len(s1)+len(s2))
+	i, j := 0, 0
+	for i < len(s1) || j < len(s2) {
+		if j >= len(s2) || (i < len(s1) && s1[i] <= s2[j]) {
+			result = appendIfNotLast(result, s1[i])
+			i++
+		} else {
+			result = appendIfNotLast(result, s2[j])
+			j++
+		}
+	}
+	return result
+}
+
+// DiffSorted 计算两个升序切片的差集，即存在于 s1 但不存在于 s2 的元素，时间复杂度为 O(n+m)
+// 返回的新切片已去重且保持升序
+func DiffSorted[T cmp.Ordered](s1 []T, s2 []T) []T {
+	result := make([]T, 0, len(s1))
+	j := 0
+	for _, item := range s1 {
+		for j < len(s2) && s2[j] < item {
+			j++
+		}
+		if j < len(s2) && s2[j] == item {
+			continue
+		}
+		result = appendIfNotLast(result, item)
+	}
+	return result
+}
+
+// appendIfNotLast 当 item 与 result 的最后一个元素不相等时才追加，用于有序切片的去重
+func appendIfNotLast[T comparable](result []T, item T) []T {
+	if len(result) > 0 && result[len(result)-1] == item {
+		return result
+	}
+	return append(result, item)
+}
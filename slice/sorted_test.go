@@ -0,0 +1,200 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertSorted(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []int
+		item int
+		want []int
+	}{
+		{
+			name: "nil slice",
+			data: nil,
+			item: 1,
+			want: []int{1},
+		},
+		{
+			name: "插入到开头",
+			data: []int{2, 3},
+			item: 1,
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "插入到中间",
+			data: []int{1, 3, 3, 5},
+			item: 3,
+			want: []int{1, 3, 3, 3, 5},
+		},
+		{
+			name: "插入到末尾",
+			data: []int{1, 2},
+			item: 3,
+			want: []int{1, 2, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := append([]int(nil), tt.data...)
+			assert.Equal(t, tt.want, InsertSorted(tt.data, tt.item))
+			assert.Equal(t, origin, tt.data)
+		})
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    []int
+		b    []int
+		want []int
+	}{
+		{
+			name: "两个 nil 切片",
+			a:    nil,
+			b:    nil,
+			want: []int{},
+		},
+		{
+			name: "其中一个为空切片",
+			a:    []int{1, 2},
+			b:    []int{},
+			want: []int{1, 2},
+		},
+		{
+			name: "交错合并并保留重复元素",
+			a:    []int{1, 3, 5, 5},
+			b:    []int{2, 3, 6},
+			want: []int{1, 2, 3, 3, 5, 5, 6},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MergeSorted(tt.a, tt.b))
+		})
+	}
+}
+
+func TestIntersectionSetSorted(t *testing.T) {
+	testCases := []struct {
+		name string
+		s1   []int
+		s2   []int
+		want []int
+	}{
+		{
+			name: "Takes the intersection of two nil slices",
+			s1:   nil,
+			s2:   nil,
+			want: []int{},
+		},
+		{
+			name: "Takes the intersection of two slices and get empty slice",
+			s1:   []int{0, 2, 3},
+			s2:   []int{1, 4, 5},
+			want: []int{},
+		},
+		{
+			name: "Takes the intersection between two slices with duplicate elements",
+			s1:   []int{1, 2, 2, 3, 5},
+			s2:   []int{2, 2, 3, 4, 5},
+			want: []int{2, 3, 5},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IntersectionSetSorted(tt.s1, tt.s2))
+		})
+	}
+}
+
+func TestUnionSorted(t *testing.T) {
+	testCases := []struct {
+		name string
+		s1   []int
+		s2   []int
+		want []int
+	}{
+		{
+			name: "Takes the union set in two nil slices",
+			s1:   nil,
+			s2:   nil,
+			want: []int{},
+		},
+		{
+			name: "Takes the union of two slices where one slice is empty",
+			s1:   []int{},
+			s2:   []int{1, 1, 2},
+			want: []int{1, 2},
+		},
+		{
+			name: "Takes the union of two slices with duplicate elements",
+			s1:   []int{1, 2, 3, 4},
+			s2:   []int{1, 2, 2, 3, 5},
+			want: []int{1, 2, 3, 4, 5},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, UnionSorted(tt.s1, tt.s2))
+		})
+	}
+}
+
+func TestDiffSorted(t *testing.T) {
+	testCases := []struct {
+		name string
+		s1   []int
+		s2   []int
+		want []int
+	}{
+		{
+			name: "两个 nil 切片",
+			s1:   nil,
+			s2:   nil,
+			want: []int{},
+		},
+		{
+			name: "s2 为空切片",
+			s1:   []int{1, 1, 2},
+			s2:   []int{},
+			want: []int{1, 2},
+		},
+		{
+			name: "存在相同元素",
+			s1:   []int{1, 2, 2, 3, 4, 6},
+			s2:   []int{0, 2, 4, 5},
+			want: []int{1, 3, 6},
+		},
+		{
+			name: "s1 为 s2 的子集",
+			s1:   []int{2, 4},
+			s2:   []int{1, 2, 3, 4},
+			want: []int{},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffSorted(tt.s1, tt.s2))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync/atomic"
+)
+
+// AtomicValue 是类型安全的 atomic.Value，零值可直接使用，此时 Load 返回 T 的零值
+// AtomicValue is a type-safe atomic.Value. The zero value is ready to use and Load returns the zero value of T.
+type AtomicValue[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// NewAtomicValue 创建一个初始值为 val 的 AtomicValue
+// NewAtomicValue creates an AtomicValue whose initial value is val.
+func NewAtomicValue[T any](val T) *AtomicValue[T] {
+	v := &AtomicValue[T]{}
+	v.Store(val)
+	return v
+}
+
+// Load 返回当前的值
+// Load returns the current value.
+func (v *AtomicValue[T]) Load() T {
+	return deref(v.p.Load())
+}
+
+// Store 将值设置为 val
+// Store sets the value to val.
+func (v *AtomicValue[T]) Store(val T) {
+	v.p.Store(&val)
+}
+
+// Swap 将值设置为 val 并返回原来的值
+// Swap sets the value to val and returns the old value.
+func (v *AtomicValue[T]) Swap(val T) T {
+	return deref(v.p.Swap(&val))
+}
+
+// Update 以 CAS 循环的方式将值更新为 fn 的返回值，并返回更新后的值；并发更新时 fn 可能被调用多次，因此 fn 不应有副作用
+// Update sets the value to the result of fn in a CAS loop and returns the new value.
+// fn may be called more than once under contention, so it should have no side effects.
+func (v *AtomicValue[T]) Update(fn func(old T) T) T {
+	for {
+		old := v.p.Load()
+		val := fn(deref(old))
+		if v.p.CompareAndSwap(old, &val) {
+			return val
+		}
+	}
+}
+
+// ComparableAtomicValue 是值可比较的 AtomicValue，额外支持 CompareAndSwap，零值可直接使用
+// ComparableAtomicValue is an AtomicValue of comparable values that also supports CompareAndSwap. The zero value is ready to use.
+type ComparableAtomicValue[T comparable] struct {
+	AtomicValue[T]
+}
+
+// NewComparableAtomicValue 创建一个初始值为 val 的 ComparableAtomicValue
+// NewComparableAtomicValue creates a ComparableAtomicValue whose initial value is val.
+func NewComparableAtomicValue[T comparable](val T) *ComparableAtomicValue[T] {
+	v := &ComparableAtomicValue[T]{}
+	v.Store(val)
+	return v
+}
+
+// CompareAndSwap 在当前值等于 old 时将值设置为 new，并返回是否设置成功
+// CompareAndSwap sets the value to new if the current value equals old, and reports whether it did.
+func (v *ComparableAtomicValue[T]) CompareAndSwap(old, new T) bool {
+	for {
+		p := v.p.Load()
+		if deref(p) != old {
+			return false
+		}
+		if v.p.CompareAndSwap(p, &new) {
+			return true
+		}
+	}
+}
+
+// deref 在 p 为 nil 时返回 T 的零值
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
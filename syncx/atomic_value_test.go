@@ -0,0 +1,125 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chenmingyong0423/gkit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicValue(t *testing.T) {
+	var v AtomicValue[[]int]
+	assert.Nil(t, v.Load())
+
+	v.Store([]int{1})
+	assert.Equal(t, []int{1}, v.Load())
+	assert.Equal(t, []int{1}, v.Swap([]int{2}))
+	assert.Equal(t, []int{2}, v.Load())
+
+	got := v.Update(func(old []int) []int {
+		return append([]int{0}, old...)
+	})
+	assert.Equal(t, []int{0, 2}, got)
+	assert.Equal(t, []int{0, 2}, v.Load())
+
+	assert.Equal(t, "a", NewAtomicValue("a").Load())
+}
+
+func TestAtomicValue_Update(t *testing.T) {
+	v := NewAtomicValue(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v.Update(func(old int) int {
+					return old + 1
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10000, v.Load())
+}
+
+func TestComparableAtomicValue_CompareAndSwap(t *testing.T) {
+	testCases := []struct {
+		name    string
+		initial *string
+		old     string
+		new     string
+		wantOK  bool
+		want    string
+	}{
+		{
+			name:   "零值",
+			old:    "",
+			new:    "a",
+			wantOK: true,
+			want:   "a",
+		},
+		{
+			name:    "当前值等于 old",
+			initial: gkit.ToPtr("a"),
+			old:     "a",
+			new:     "b",
+			wantOK:  true,
+			want:    "b",
+		},
+		{
+			name:    "当前值不等于 old",
+			initial: gkit.ToPtr("a"),
+			old:     "b",
+			new:     "c",
+			want:    "a",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var v ComparableAtomicValue[string]
+			if tt.initial != nil {
+				v.Store(*tt.initial)
+			}
+			assert.Equal(t, tt.wantOK, v.CompareAndSwap(tt.old, tt.new))
+			assert.Equal(t, tt.want, v.Load())
+		})
+	}
+}
+
+func TestComparableAtomicValue_Concurrent(t *testing.T) {
+	v := NewComparableAtomicValue(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for {
+					old := v.Load()
+					if v.CompareAndSwap(old, old+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10000, v.Load())
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBarrierBroken 表示屏障已经损坏，例如有参与者放弃了等待，损坏的屏障需要调用 Reset 后才能继续使用
+// ErrBarrierBroken is returned when the barrier is broken, e.g. a party gave up waiting.
+// A broken barrier can only be used again after Reset.
+var ErrBarrierBroken = errors.New("syncx: barrier is broken")
+
+// Barrier 是可重复使用的循环屏障，每当 n 个参与者都到达时一起放行，并开始下一轮
+// Barrier is a reusable cyclic barrier, n parties are released together each time all of them arrive, and then the next cycle begins.
+type Barrier struct {
+	parties int
+	action  func()
+
+	mu      sync.Mutex
+	gen     *barrierGeneration
+	arrived int
+}
+
+type barrierGeneration struct {
+	done   chan struct{}
+	broken bool
+}
+
+// NewBarrier 创建一个有 n 个参与者的 Barrier，action 不为 nil 时在每一轮所有参与者到达后、放行前由最后到达的参与者执行
+// action 在屏障的锁内执行，不能调用该屏障的方法；action 发生 panic 时屏障损坏，最后到达的参与者得到 *PanicError
+// n 小于 1 时按 1 处理
+// NewBarrier creates a Barrier with n parties. If action is not nil, it is run by the last arriving party
+// after all parties arrive and before they are released in each cycle.
+// action runs while the barrier is locked and must not call the methods of the barrier.
+// If action panics, the barrier is broken and the last arriving party gets a *PanicError.
+// n less than 1 is treated as 1.
+func NewBarrier(n int, action func()) *Barrier {
+	return &Barrier{
+		parties: max(n, 1),
+		action:  action,
+		gen:     newBarrierGeneration(),
+	}
+}
+
+// Await 等待所有参与者到达本轮屏障
+// ctx 先结束时返回 ctx.Err() 并损坏屏障，其他等待者返回 ErrBarrierBroken；屏障已经损坏时立即返回 ErrBarrierBroken
+// Await waits for all parties to arrive at the barrier in the current cycle.
+// If ctx is done first, it returns ctx.Err() and breaks the barrier, and the other waiters return ErrBarrierBroken.
+// It returns ErrBarrierBroken immediately if the barrier is already broken.
+func (b *Barrier) Await(ctx context.Context) error {
+	b.mu.Lock()
+	g := b.gen
+	if g.broken {
+		b.mu.Unlock()
+		return ErrBarrierBroken
+	}
+	b.arrived++
+	if b.arrived == b.parties {
+		defer b.mu.Unlock()
+		return b.trip()
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-g.done:
+		return b.result(g)
+	case <-ctx.Done():
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-g.done:
+		// 在 ctx 结束的同时被放行或屏障已经损坏
+		if g.broken {
+			return ErrBarrierBroken
+		}
+		return nil
+	default:
+	}
+	b.breakLocked()
+	return ctx.Err()
+}
+
+// Reset 损坏当前这一轮（有参与者在等待时，它们返回 ErrBarrierBroken）并开始新的一轮
+// Reset breaks the current cycle, so that the waiting parties if any return ErrBarrierBroken, and begins a new cycle.
+func (b *Barrier) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.gen.broken && b.arrived > 0 {
+		b.breakLocked()
+	}
+	b.gen = newBarrierGeneration()
+	b.arrived = 0
+}
+
+// Parties 返回参与者的数量
+// Parties returns the number of parties.
+func (b *Barrier) Parties() int {
+	return b.parties
+}
+
+// Waiting 返回本轮中正在等待的参与者数量
+// Waiting returns the number of parties waiting in the current cycle.
+func (b *Barrier) Waiting() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.gen.broken {
+		return 0
+	}
+	return b.arrived
+}
+
+// Broken 报告屏障是否已经损坏
+// Broken reports whether the barrier is broken.
+func (b *Barrier) Broken() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.gen.broken
+}
+
+// trip 执行 action 并放行本轮的参与者，调用方需持有 b.mu
+func (b *Barrier) trip() error {
+	if b.action != nil {
+		if err := callSafely(func() error {
+			b.action()
+			return nil
+		}); err != nil {
+			b.breakLocked()
+			return err
+		}
+	}
+	close(b.gen.done)
+	b.gen = newBarrierGeneration()
+	b.arrived = 0
+	return nil
+}
+
+// breakLocked 损坏当前这一轮并唤醒等待者，调用方需持有 b.mu
+func (b *Barrier) breakLocked() {
+	b.gen.broken = true
+	close(b.gen.done)
+}
+
+func (b *Barrier) result(g *barrierGeneration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if g.broken {
+		return ErrBarrierBroken
+	}
+	return nil
+}
+
+func newBarrierGeneration() *barrierGeneration {
+	return &barrierGeneration{
+		done: make(chan struct{}),
+	}
+}
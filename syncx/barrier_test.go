@@ -0,0 +1,139 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarrier(t *testing.T) {
+	const (
+		parties = 5
+		cycles  = 10
+	)
+	var (
+		trips   atomic.Int64
+		arrived atomic.Int64
+	)
+	b := NewBarrier(parties, func() {
+		// 所有参与者都到达本轮后才执行
+		assert.Equal(t, (trips.Load()+1)*parties, arrived.Load())
+		trips.Add(1)
+	})
+	assert.Equal(t, parties, b.Parties())
+
+	var wg sync.WaitGroup
+	for i := 0; i < parties; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := 0; c < cycles; c++ {
+				arrived.Add(1)
+				assert.NoError(t, b.Await(context.Background()))
+				// 放行时 action 已经执行
+				assert.GreaterOrEqual(t, trips.Load(), int64(c+1))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(cycles), trips.Load())
+	assert.Equal(t, 0, b.Waiting())
+}
+
+func TestBarrier_Broken(t *testing.T) {
+	b := NewBarrier(3, nil)
+	errs := make(chan error)
+	go func() {
+		errs <- b.Await(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		return b.Waiting() == 1
+	}, time.Second, time.Millisecond)
+
+	// 放弃等待的参与者损坏屏障，其他等待者返回 ErrBarrierBroken
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Await(ctx))
+	assert.Equal(t, ErrBarrierBroken, <-errs)
+	assert.True(t, b.Broken())
+	assert.Equal(t, 0, b.Waiting())
+	assert.Equal(t, ErrBarrierBroken, b.Await(context.Background()))
+
+	// 重置后可以继续使用
+	b.Reset()
+	assert.False(t, b.Broken())
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, b.Await(context.Background()))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBarrier_Reset(t *testing.T) {
+	b := NewBarrier(2, nil)
+	errs := make(chan error)
+	go func() {
+		errs <- b.Await(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		return b.Waiting() == 1
+	}, time.Second, time.Millisecond)
+	b.Reset()
+	assert.Equal(t, ErrBarrierBroken, <-errs)
+	assert.False(t, b.Broken())
+	assert.Equal(t, 0, b.Waiting())
+}
+
+func TestBarrier_ActionPanic(t *testing.T) {
+	b := NewBarrier(2, func() {
+		panic("boom")
+	})
+	errs := make(chan error)
+	go func() {
+		errs <- b.Await(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		return b.Waiting() == 1
+	}, time.Second, time.Millisecond)
+
+	err := b.Await(context.Background())
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, ErrBarrierBroken, <-errs)
+	assert.True(t, b.Broken())
+}
+
+func TestBarrier_SingleParty(t *testing.T) {
+	calls := 0
+	b := NewBarrier(0, func() {
+		calls++
+	})
+	assert.Equal(t, 1, b.Parties())
+	assert.NoError(t, b.Await(context.Background()))
+	assert.NoError(t, b.Await(context.Background()))
+	assert.Equal(t, 2, calls)
+}
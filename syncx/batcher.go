@@ -0,0 +1,179 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBatcherClosed 表示 Batcher 已经关闭，不再接收数据
+// ErrBatcherClosed is returned when adding to a Batcher that has been closed.
+var ErrBatcherClosed = errors.New("syncx: batcher is closed")
+
+// Batcher 收集数据并分批处理，当收集的数据达到 size 个或每隔 interval 时间时调用 flush 处理当前批次
+// flush 不会被并发调用，各批次按照收集的顺序处理；flush 中可以调用 Add，但不能调用 Flush 和 Close
+// Batcher collects items and processes them in batches, flush is called with the current batch
+// when size items are collected or every interval.
+// flush is never called concurrently and the batches are processed in the order they are collected.
+// flush may call Add, but must not call Flush or Close.
+type Batcher[T any] struct {
+	flush    func(items []T)
+	size     int
+	interval time.Duration
+	clock    Clock
+
+	mu     sync.Mutex
+	items  []T
+	closed bool
+	// pending 是等待处理的批次，由正在处理批次的 goroutine 按顺序取出处理
+	pending [][]T
+	// queued 和 processed 分别是加入 pending 和处理完成的批次数量，Flush 据此等待之前的批次处理完成
+	queued    uint64
+	processed uint64
+	// flushing 表示是否有 goroutine 正在处理批次，cond 在处理完一个批次或 flushing 变化时广播
+	flushing bool
+	cond     *sync.Cond
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewBatcher 创建一个 Batcher，size 小于等于 0 时不按数量处理，interval 小于等于 0 时不定时处理，clock 为 nil 时使用 SystemClock
+// NewBatcher creates a Batcher, items are not flushed by size if size <= 0 and not flushed periodically if interval <= 0.
+// SystemClock is used if clock is nil.
+func NewBatcher[T any](flush func(items []T), size int, interval time.Duration, clock Clock) *Batcher[T] {
+	b := &Batcher[T]{
+		flush:    flush,
+		size:     size,
+		interval: interval,
+		clock:    orSystemClock(clock),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	if interval > 0 {
+		go b.tick()
+	} else {
+		close(b.done)
+	}
+	return b
+}
+
+// Add 添加一个数据，数据达到 size 个时在当前 goroutine 中处理该批次，Batcher 关闭后返回 ErrBatcherClosed
+// 如果其他调用正在处理批次，该批次会交由其在之后处理，Add 立即返回
+// Add adds an item and flushes the batch in the calling goroutine when it reaches size items.
+// If a batch is being processed by another call, the batch is handed over to it and Add returns right away.
+// ErrBatcherClosed is returned after the Batcher is closed.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBatcherClosed
+	}
+	b.items = append(b.items, item)
+	if b.size <= 0 || len(b.items) < b.size {
+		b.mu.Unlock()
+		return nil
+	}
+	b.flushLocked(false)
+	return nil
+}
+
+// Flush 立即处理当前收集的数据，并等待此前收集的所有数据都处理完成后返回，没有数据时不做任何事
+// 其他调用正在处理批次时，当前收集的数据交由其处理，Flush 等待其处理完成，因此不能在 flush 中调用
+// Flush processes the collected items right away and returns after all the items collected so far are processed,
+// it does nothing if there is none. If a batch is being processed by another call, the items are handed over
+// and Flush waits for them to be processed, so it must not be called in flush.
+func (b *Batcher[T]) Flush() {
+	b.mu.Lock()
+	b.flushLocked(true)
+}
+
+// Close 停止接收数据并处理剩余的数据，重复调用不做任何事
+// Close stops accepting items and flushes the remaining ones, calling it again does nothing.
+func (b *Batcher[T]) Close() {
+	b.once.Do(func() {
+		b.mu.Lock()
+		b.closed = true
+		b.mu.Unlock()
+		close(b.stop)
+		<-b.done
+		b.Flush()
+		// 等待 flush 中调用 Add 加入的批次也处理完成
+		b.mu.Lock()
+		for b.flushing {
+			b.cond.Wait()
+		}
+		b.mu.Unlock()
+	})
+}
+
+// flushLocked 将当前批次加入待处理的批次，没有其他调用正在处理批次时在当前 goroutine 中按顺序处理所有批次
+// 否则 wait 为 false 时直接返回，为 true 时等待此前加入的批次处理完成；正在处理的调用因 flush panic 退出时由当前调用接手
+// 处理批次时不持有 b.mu，因此 flush 中调用 Add 只会加入新的批次而不会死锁
+// 调用方需持有 b.mu，返回前会释放 b.mu
+func (b *Batcher[T]) flushLocked(wait bool) {
+	if len(b.items) > 0 {
+		b.pending = append(b.pending, b.items)
+		b.items = nil
+		b.queued++
+	}
+	target := b.queued
+	for b.flushing {
+		if !wait || b.processed >= target {
+			b.mu.Unlock()
+			return
+		}
+		b.cond.Wait()
+	}
+	b.flushing = true
+	defer func() {
+		b.flushing = false
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	}()
+	for len(b.pending) > 0 {
+		items := b.pending[0]
+		b.pending[0] = nil
+		b.pending = b.pending[1:]
+		b.process(items)
+	}
+}
+
+// process 在不持有 b.mu 的情况下处理一个批次，调用方需持有 b.mu，flush panic 时也会重新获取 b.mu 并将该批次记为处理完成
+func (b *Batcher[T]) process(items []T) {
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.processed++
+		b.cond.Broadcast()
+	}()
+	b.flush(items)
+}
+
+func (b *Batcher[T]) tick() {
+	defer close(b.done)
+	for {
+		select {
+		case <-b.clock.After(b.interval):
+			b.Flush()
+		case <-b.stop:
+			return
+		}
+	}
+}
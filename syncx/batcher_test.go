@@ -0,0 +1,201 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcher_Size(t *testing.T) {
+	var batches [][]int
+	b := NewBatcher(func(items []int) {
+		batches = append(batches, items)
+	}, 3, 0, nil)
+
+	for i := 1; i <= 7; i++ {
+		require.NoError(t, b.Add(i))
+	}
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}}, batches)
+
+	b.Flush()
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}, batches)
+	// 没有数据时不处理
+	b.Flush()
+	assert.Len(t, batches, 3)
+
+	require.NoError(t, b.Add(8))
+	b.Close()
+	b.Close()
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7}, {8}}, batches)
+	assert.Equal(t, ErrBatcherClosed, b.Add(9))
+}
+
+func TestBatcher_Interval(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var (
+		mu      sync.Mutex
+		batches [][]string
+	)
+	b := NewBatcher(func(items []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, items)
+	}, 0, time.Second, clock)
+	getBatches := func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+
+	require.NoError(t, b.Add("a"))
+	require.NoError(t, b.Add("b"))
+	advanceWhenWaiting(t, clock, time.Second)
+	assert.Eventually(t, func() bool {
+		return len(getBatches()) == 1
+	}, time.Second, time.Millisecond)
+
+	advanceWhenWaiting(t, clock, time.Second)
+	require.NoError(t, b.Add("c"))
+	b.Close()
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, getBatches())
+}
+
+func TestBatcher_Reentrant(t *testing.T) {
+	var (
+		b       *Batcher[int]
+		batches [][]int
+	)
+	b = NewBatcher(func(items []int) {
+		batches = append(batches, items)
+		// 在 flush 中添加数据并填满批次，不会死锁，新的批次在当前批次之后处理
+		for _, item := range items {
+			if item < 10 {
+				assert.NoError(t, b.Add(item*10))
+			}
+		}
+	}, 2, 0, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, b.Add(1))
+		require.NoError(t, b.Add(2))
+		require.NoError(t, b.Add(3))
+		require.NoError(t, b.Add(4))
+		b.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlock")
+	}
+	assert.Equal(t, [][]int{{1, 2}, {10, 20}, {3, 4}, {30, 40}}, batches)
+}
+
+func TestBatcher_FlushWaits(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]int
+	)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	b := NewBatcher(func(items []int) {
+		if items[0] == 1 {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, items)
+	}, 1, 0, nil)
+	getBatches := func() [][]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+
+	go func() {
+		assert.NoError(t, b.Add(1))
+	}()
+	<-started
+	// 其他调用正在处理批次时，Add 交由其处理后立即返回
+	require.NoError(t, b.Add(2))
+
+	// Flush 等待此前收集的所有数据都处理完成后才返回
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		b.Flush()
+	}()
+	select {
+	case <-flushed:
+		t.Fatal("Flush returned before the batches were processed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	<-flushed
+	assert.Equal(t, [][]int{{1}, {2}}, getBatches())
+	b.Close()
+}
+
+func TestBatcher_Panic(t *testing.T) {
+	var batches [][]int
+	b := NewBatcher(func(items []int) {
+		if items[0] == 1 {
+			panic("mock panic")
+		}
+		batches = append(batches, items)
+	}, 1, 0, nil)
+
+	assert.Panics(t, func() {
+		_ = b.Add(1)
+	})
+	// flush panic 后仍可以继续处理批次
+	require.NoError(t, b.Add(2))
+	b.Close()
+	assert.Equal(t, [][]int{{2}}, batches)
+}
+
+func TestBatcher_Concurrent(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		total int
+	)
+	b := NewBatcher(func(items []int) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.LessOrEqual(t, len(items), 10)
+		total += len(items)
+	}, 10, time.Millisecond, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, b.Add(j))
+			}
+		}()
+	}
+	wg.Wait()
+	b.Close()
+	assert.Equal(t, 1000, total)
+}
@@ -0,0 +1,391 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen 表示熔断器处于打开状态，请求被拒绝
+	// ErrCircuitOpen is returned when the circuit breaker is open and the request is rejected.
+	ErrCircuitOpen = errors.New("syncx: circuit breaker is open")
+	// ErrTooManyProbes 表示熔断器处于半开状态，且探测请求数已经达到上限
+	// ErrTooManyProbes is returned when the circuit breaker is half-open and the number of probe requests reaches the limit.
+	ErrTooManyProbes = errors.New("syncx: too many probe requests in half-open state")
+)
+
+// CircuitState 是熔断器的状态
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// StateClosed 表示请求正常放行
+	// StateClosed means requests are allowed.
+	StateClosed CircuitState = iota
+	// StateOpen 表示请求被拒绝
+	// StateOpen means requests are rejected.
+	StateOpen
+	// StateHalfOpen 表示放行有限的探测请求，以判断下游是否恢复
+	// StateHalfOpen means a limited number of probe requests are allowed to check whether the downstream has recovered.
+	StateHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitCounts 是熔断器在关闭状态下的请求统计，Requests、Successes 和 Failures 只统计滚动窗口内的请求
+// CircuitCounts are the request counts of a closed circuit breaker, Requests, Successes and Failures only count the requests in the rolling window.
+type CircuitCounts struct {
+	Requests            int64
+	Successes           int64
+	Failures            int64
+	ConsecutiveFailures int64
+}
+
+// TripPolicy 根据请求统计判断熔断器是否应该打开，在每次请求失败后调用
+// TripPolicy decides whether the circuit breaker should open according to the counts, it is called after each failed request.
+type TripPolicy func(counts CircuitCounts) bool
+
+// ConsecutiveFailures 返回在连续失败次数达到 n 时打开熔断器的策略
+// ConsecutiveFailures returns a policy that opens the circuit breaker after n consecutive failures.
+func ConsecutiveFailures(n int64) TripPolicy {
+	return func(counts CircuitCounts) bool {
+		return counts.ConsecutiveFailures >= n
+	}
+}
+
+// FailureRatio 返回在滚动窗口内的请求数不少于 minRequests，且失败率不低于 ratio 时打开熔断器的策略
+// FailureRatio returns a policy that opens the circuit breaker when there are at least minRequests requests in the rolling window
+// and the failure ratio is at least ratio.
+func FailureRatio(ratio float64, minRequests int64) TripPolicy {
+	return func(counts CircuitCounts) bool {
+		return counts.Requests > 0 && counts.Requests >= minRequests &&
+			float64(counts.Failures)/float64(counts.Requests) >= ratio
+	}
+}
+
+// CircuitBreakerOption 是 CircuitBreaker 的可选配置
+// CircuitBreakerOption configures a CircuitBreaker.
+type CircuitBreakerOption func(o *circuitBreakerOptions)
+
+type circuitBreakerOptions struct {
+	tripPolicy       TripPolicy
+	openTimeout      time.Duration
+	halfOpenRequests int64
+	window           time.Duration
+	buckets          int
+	isFailure        func(err error) bool
+	onStateChange    func(from, to CircuitState)
+	clock            Clock
+}
+
+// WithTripPolicy 设置打开熔断器的策略，默认为 ConsecutiveFailures(5)
+// WithTripPolicy sets the policy to open the circuit breaker, ConsecutiveFailures(5) by default.
+func WithTripPolicy(policy TripPolicy) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.tripPolicy = policy
+	}
+}
+
+// WithOpenTimeout 设置熔断器打开后转为半开状态前等待的时间，默认为 60 秒
+// WithOpenTimeout sets how long the circuit breaker stays open before it turns half-open, 60 seconds by default.
+func WithOpenTimeout(d time.Duration) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.openTimeout = d
+	}
+}
+
+// WithHalfOpenRequests 设置半开状态下放行的探测请求数，这些请求全部成功后熔断器关闭，默认为 1
+// WithHalfOpenRequests sets the number of probe requests allowed in half-open state,
+// the circuit breaker closes after all of them succeed. 1 by default.
+func WithHalfOpenRequests(n int64) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.halfOpenRequests = max(n, 1)
+	}
+}
+
+// WithRollingWindow 设置统计请求的滚动窗口长度以及窗口划分的桶数，默认为 60 秒和 10 个桶
+// WithRollingWindow sets the length of the rolling window for counting requests and the number of buckets it is divided into,
+// 60 seconds and 10 buckets by default.
+func WithRollingWindow(window time.Duration, buckets int) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.window = window
+		o.buckets = max(buckets, 1)
+	}
+}
+
+// WithFailureClassifier 设置判断错误是否计为失败的函数，默认所有非 nil 的错误都计为失败
+// WithFailureClassifier sets the function that decides whether an error counts as a failure, every non-nil error does by default.
+func WithFailureClassifier(isFailure func(err error) bool) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.isFailure = isFailure
+	}
+}
+
+// WithStateChangeHandler 设置状态变化时的回调，回调在熔断器的锁内执行，不能调用该熔断器的方法
+// WithStateChangeHandler sets the callback invoked on state changes,
+// it runs while the circuit breaker is locked and must not call the methods of the circuit breaker.
+func WithStateChangeHandler(fn func(from, to CircuitState)) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.onStateChange = fn
+	}
+}
+
+// WithCircuitBreakerClock 设置熔断器使用的时钟，默认为 SystemClock
+// WithCircuitBreakerClock sets the clock used by the circuit breaker, SystemClock by default.
+func WithCircuitBreakerClock(clock Clock) CircuitBreakerOption {
+	return func(o *circuitBreakerOptions) {
+		o.clock = orSystemClock(clock)
+	}
+}
+
+// CircuitBreaker 是熔断器，在下游持续失败时拒绝请求，并在一段时间后通过半开状态的探测请求判断下游是否恢复
+// CircuitBreaker rejects requests while the downstream keeps failing,
+// and checks whether the downstream has recovered with probe requests in half-open state after a while.
+type CircuitBreaker[T any] struct {
+	opts circuitBreakerOptions
+
+	mu    sync.Mutex
+	state CircuitState
+	// generation 在每次状态变化时递增，用于忽略在之前的状态中开始的请求的结果
+	generation          uint64
+	openUntil           time.Time
+	window              rollingCounts
+	consecutiveFailures int64
+	probes              int64
+	probeSuccesses      int64
+}
+
+// NewCircuitBreaker 创建一个处于关闭状态的 CircuitBreaker
+// NewCircuitBreaker creates a CircuitBreaker in closed state.
+func NewCircuitBreaker[T any](opts ...CircuitBreakerOption) *CircuitBreaker[T] {
+	o := circuitBreakerOptions{
+		tripPolicy:       ConsecutiveFailures(5),
+		openTimeout:      60 * time.Second,
+		halfOpenRequests: 1,
+		window:           60 * time.Second,
+		buckets:          10,
+		isFailure: func(err error) bool {
+			return err != nil
+		},
+		clock: SystemClock,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cb := &CircuitBreaker[T]{
+		opts: o,
+	}
+	cb.window.init(o.window, o.buckets, o.clock.Now())
+	return cb
+}
+
+// Execute 在熔断器允许时执行 fn，并根据其结果更新熔断器的状态
+// 熔断器打开时返回 ErrCircuitOpen，半开状态下探测请求数达到上限时返回 ErrTooManyProbes，ctx 已经结束时返回 ctx.Err()，这些情况下 fn 不会被执行
+// fn 中发生的 panic 会被转换为 *PanicError 返回，并计为一次失败
+// Execute runs fn if the circuit breaker allows it and updates the state according to the result.
+// It returns ErrCircuitOpen if the circuit breaker is open, ErrTooManyProbes if the probe limit in half-open state is reached,
+// and ctx.Err() if ctx is already done, fn is not run in these cases.
+// A panic in fn is returned as a *PanicError and counts as a failure.
+func (cb *CircuitBreaker[T]) Execute(ctx context.Context, fn func() (T, error)) (T, error) {
+	var val T
+	if err := ctx.Err(); err != nil {
+		return val, err
+	}
+	generation, err := cb.before()
+	if err != nil {
+		return val, err
+	}
+	err = callSafely(func() (err error) {
+		val, err = fn()
+		return err
+	})
+	cb.after(generation, err)
+	return val, err
+}
+
+// State 返回熔断器当前的状态
+// State returns the current state of the circuit breaker.
+func (cb *CircuitBreaker[T]) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.refresh(cb.opts.clock.Now())
+	return cb.state
+}
+
+// Counts 返回熔断器当前的请求统计
+// Counts returns the current request counts of the circuit breaker.
+func (cb *CircuitBreaker[T]) Counts() CircuitCounts {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.counts(cb.opts.clock.Now())
+}
+
+func (cb *CircuitBreaker[T]) before() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.refresh(cb.opts.clock.Now())
+	switch cb.state {
+	case StateOpen:
+		return 0, ErrCircuitOpen
+	case StateHalfOpen:
+		if cb.probes >= cb.opts.halfOpenRequests {
+			return 0, ErrTooManyProbes
+		}
+		cb.probes++
+	}
+	return cb.generation, nil
+}
+
+func (cb *CircuitBreaker[T]) after(generation uint64, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.opts.clock.Now()
+	cb.refresh(now)
+	if generation != cb.generation {
+		return
+	}
+	failed := err != nil && (cb.opts.isFailure(err) || errors.As(err, new(*PanicError)))
+	switch cb.state {
+	case StateClosed:
+		cb.window.add(now, failed)
+		if !failed {
+			cb.consecutiveFailures = 0
+			return
+		}
+		cb.consecutiveFailures++
+		if cb.opts.tripPolicy(cb.counts(now)) {
+			cb.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if failed {
+			cb.setState(StateOpen, now)
+			return
+		}
+		cb.probeSuccesses++
+		if cb.probeSuccesses >= cb.opts.halfOpenRequests {
+			cb.setState(StateClosed, now)
+		}
+	}
+}
+
+// refresh 在打开状态超时后将熔断器转为半开状态，调用方需持有 cb.mu
+func (cb *CircuitBreaker[T]) refresh(now time.Time) {
+	if cb.state == StateOpen && !now.Before(cb.openUntil) {
+		cb.setState(StateHalfOpen, now)
+	}
+}
+
+// counts 返回当前的请求统计，调用方需持有 cb.mu
+func (cb *CircuitBreaker[T]) counts(now time.Time) CircuitCounts {
+	requests, failures := cb.window.sum(now)
+	return CircuitCounts{
+		Requests:            requests,
+		Successes:           requests - failures,
+		Failures:            failures,
+		ConsecutiveFailures: cb.consecutiveFailures,
+	}
+}
+
+// setState 切换熔断器的状态并重置统计，调用方需持有 cb.mu
+func (cb *CircuitBreaker[T]) setState(state CircuitState, now time.Time) {
+	from := cb.state
+	cb.state = state
+	cb.generation++
+	cb.window.reset(now)
+	cb.consecutiveFailures = 0
+	cb.probes = 0
+	cb.probeSuccesses = 0
+	if state == StateOpen {
+		cb.openUntil = now.Add(cb.opts.openTimeout)
+	}
+	if cb.opts.onStateChange != nil {
+		cb.opts.onStateChange(from, state)
+	}
+}
+
+// rollingCounts 是划分为若干个桶的滚动窗口，每个桶统计一段时间内的请求数和失败数
+type rollingCounts struct {
+	bucketSize time.Duration
+	buckets    []rollingBucket
+	// head 是当前桶的下标，headStart 是当前桶的起始时间
+	head      int
+	headStart time.Time
+}
+
+type rollingBucket struct {
+	requests int64
+	failures int64
+}
+
+func (r *rollingCounts) init(window time.Duration, buckets int, now time.Time) {
+	r.bucketSize = max(window/time.Duration(buckets), 1)
+	r.buckets = make([]rollingBucket, buckets)
+	r.reset(now)
+}
+
+func (r *rollingCounts) reset(now time.Time) {
+	clear(r.buckets)
+	r.head = 0
+	r.headStart = now
+}
+
+// advance 将窗口滚动到 now 所在的桶，并清空滚出窗口的桶
+func (r *rollingCounts) advance(now time.Time) {
+	steps := int64(now.Sub(r.headStart) / r.bucketSize)
+	if steps <= 0 {
+		return
+	}
+	r.headStart = r.headStart.Add(time.Duration(steps) * r.bucketSize)
+	if steps >= int64(len(r.buckets)) {
+		clear(r.buckets)
+		return
+	}
+	for i := int64(0); i < steps; i++ {
+		r.head = (r.head + 1) % len(r.buckets)
+		r.buckets[r.head] = rollingBucket{}
+	}
+}
+
+func (r *rollingCounts) add(now time.Time, failed bool) {
+	r.advance(now)
+	r.buckets[r.head].requests++
+	if failed {
+		r.buckets[r.head].failures++
+	}
+}
+
+func (r *rollingCounts) sum(now time.Time) (requests, failures int64) {
+	r.advance(now)
+	for _, b := range r.buckets {
+		requests += b.requests
+		failures += b.failures
+	}
+	return requests, failures
+}
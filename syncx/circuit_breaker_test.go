@@ -0,0 +1,219 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", StateClosed.String())
+	assert.Equal(t, "open", StateOpen.String())
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+	assert.Equal(t, "unknown", CircuitState(100).String())
+}
+
+func TestTripPolicy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy TripPolicy
+		counts CircuitCounts
+		want   bool
+	}{
+		{
+			name:   "连续失败次数未达到阈值",
+			policy: ConsecutiveFailures(3),
+			counts: CircuitCounts{ConsecutiveFailures: 2},
+		},
+		{
+			name:   "连续失败次数达到阈值",
+			policy: ConsecutiveFailures(3),
+			counts: CircuitCounts{ConsecutiveFailures: 3},
+			want:   true,
+		},
+		{
+			name:   "请求数不足",
+			policy: FailureRatio(0.5, 10),
+			counts: CircuitCounts{Requests: 9, Failures: 9},
+		},
+		{
+			name:   "失败率未达到阈值",
+			policy: FailureRatio(0.5, 10),
+			counts: CircuitCounts{Requests: 10, Successes: 6, Failures: 4},
+		},
+		{
+			name:   "失败率达到阈值",
+			policy: FailureRatio(0.5, 10),
+			counts: CircuitCounts{Requests: 10, Successes: 5, Failures: 5},
+			want:   true,
+		},
+		{
+			name:   "没有请求",
+			policy: FailureRatio(0, 0),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy(tt.counts))
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	mockErr := errors.New("mock error")
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var transitions []string
+	cb := NewCircuitBreaker[int](
+		WithTripPolicy(ConsecutiveFailures(2)),
+		WithOpenTimeout(time.Second),
+		WithHalfOpenRequests(2),
+		WithCircuitBreakerClock(clock),
+		WithStateChangeHandler(func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		}),
+	)
+	succeed := func() (int, error) { return 1, nil }
+	fail := func() (int, error) { return 0, mockErr }
+
+	val, err := cb.Execute(context.Background(), succeed)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	_, err = cb.Execute(context.Background(), fail)
+	assert.Equal(t, mockErr, err)
+	assert.Equal(t, CircuitCounts{Requests: 2, Successes: 1, Failures: 1, ConsecutiveFailures: 1}, cb.Counts())
+
+	// 连续失败两次后打开
+	_, _ = cb.Execute(context.Background(), fail)
+	assert.Equal(t, StateOpen, cb.State())
+	_, err = cb.Execute(context.Background(), succeed)
+	assert.Equal(t, ErrCircuitOpen, err)
+
+	// 超时后转为半开状态，探测请求失败时重新打开
+	clock.Advance(time.Second)
+	assert.Equal(t, StateHalfOpen, cb.State())
+	_, err = cb.Execute(context.Background(), fail)
+	assert.Equal(t, mockErr, err)
+	assert.Equal(t, StateOpen, cb.State())
+
+	// 探测请求全部成功后关闭
+	clock.Advance(time.Second)
+	_, err = cb.Execute(context.Background(), succeed)
+	require.NoError(t, err)
+	assert.Equal(t, StateHalfOpen, cb.State())
+	_, err = cb.Execute(context.Background(), succeed)
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, CircuitCounts{}, cb.Counts())
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreaker_TooManyProbes(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := NewCircuitBreaker[string](
+		WithTripPolicy(ConsecutiveFailures(1)),
+		WithOpenTimeout(time.Second),
+		WithCircuitBreakerClock(clock),
+	)
+	_, _ = cb.Execute(context.Background(), func() (string, error) { panic("boom") })
+	require.Equal(t, StateOpen, cb.State())
+	clock.Advance(time.Second)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		defer wg.Done()
+		val, err := cb.Execute(context.Background(), func() (string, error) {
+			close(started)
+			<-release
+			return "ok", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", val)
+	}()
+	<-started
+	_, err := cb.Execute(context.Background(), func() (string, error) { return "", nil })
+	assert.Equal(t, ErrTooManyProbes, err)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	mockErr := errors.New("mock error")
+	ignoredErr := errors.New("ignored error")
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := NewCircuitBreaker[int](
+		WithTripPolicy(FailureRatio(0.5, 4)),
+		WithRollingWindow(time.Second, 4),
+		WithFailureClassifier(func(err error) bool {
+			return !errors.Is(err, ignoredErr)
+		}),
+		WithCircuitBreakerClock(clock),
+	)
+	execute := func(err error) {
+		_, _ = cb.Execute(context.Background(), func() (int, error) { return 0, err })
+	}
+
+	execute(mockErr)
+	execute(mockErr)
+	clock.Advance(500 * time.Millisecond)
+	execute(nil)
+	execute(ignoredErr)
+	assert.Equal(t, CircuitCounts{Requests: 4, Successes: 2, Failures: 2}, cb.Counts())
+
+	// 最早的请求滚出窗口
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, CircuitCounts{Requests: 2, Successes: 2}, cb.Counts())
+	execute(mockErr)
+	assert.Equal(t, StateClosed, cb.State())
+	execute(mockErr)
+	assert.Equal(t, StateOpen, cb.State())
+
+	// 整个窗口过期
+	cb = NewCircuitBreaker[int](WithCircuitBreakerClock(clock))
+	_, _ = cb.Execute(context.Background(), func() (int, error) { return 0, mockErr })
+	clock.Advance(time.Hour)
+	assert.Equal(t, CircuitCounts{ConsecutiveFailures: 1}, cb.Counts())
+}
+
+func TestCircuitBreaker_ContextDone(t *testing.T) {
+	cb := NewCircuitBreaker[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	_, err := cb.Execute(ctx, func() (int, error) {
+		called = true
+		return 0, nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, called)
+	assert.Equal(t, CircuitCounts{}, cb.Counts())
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	_ Clock = SystemClock
+	_ Clock = &FakeClock{}
+)
+
+// Clock 抽象了时间的获取与等待，便于在测试中注入可控的时钟
+// Clock abstracts reading and waiting for time, so that a controllable clock can be injected in tests.
+type Clock interface {
+	// Now 返回当前时间
+	// Now returns the current time.
+	Now() time.Time
+	// After 返回一个在 d 时间后接收到当前时间的 channel
+	// After returns a channel that receives the current time after d.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock 是基于 time 包的系统时钟
+// SystemClock is the system clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// orSystemClock 在 clock 为 nil 时返回 SystemClock
+func orSystemClock(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
+
+// FakeClock 是手动推进的时钟，用于编写确定性的测试
+// FakeClock is a manually advanced clock for writing deterministic tests.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock 创建一个以 start 为当前时间的 FakeClock
+// NewFakeClock creates a FakeClock whose current time is start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{
+		now: start,
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After 返回的 channel 在时钟被推进到 d 时间之后时接收到当前时间，d <= 0 时立即接收
+// After returns a channel that receives the current time once the clock is advanced by d, or immediately if d <= 0.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{
+		deadline: c.now.Add(d),
+		ch:       ch,
+	})
+	return ch
+}
+
+// Advance 将时钟向前推进 d，并唤醒所有到期的等待者
+// Advance moves the clock forward by d and fires all the expired waiters.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			remaining = append(remaining, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	clear(c.waiters[len(remaining):])
+	c.waiters = remaining
+}
+
+// Waiters 返回尚未到期的等待者数量，可用于在推进时钟前确认其他 goroutine 已经开始等待
+// Waiters returns the number of pending waiters, which helps to make sure other goroutines are waiting before advancing the clock.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	immediate := clock.After(0)
+	assert.Equal(t, start, <-immediate)
+
+	ch1 := clock.After(time.Second)
+	ch2 := clock.After(2 * time.Second)
+	assert.Equal(t, 2, clock.Waiters())
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Len(t, ch1, 0)
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, start.Add(time.Second), <-ch1)
+	assert.Len(t, ch2, 0)
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour+time.Second), <-ch2)
+	assert.Equal(t, 0, clock.Waiters())
+	assert.Equal(t, start.Add(time.Hour+time.Second), clock.Now())
+}
+
+func TestSystemClock(t *testing.T) {
+	before := time.Now()
+	assert.False(t, SystemClock.Now().Before(before))
+	select {
+	case <-SystemClock.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("SystemClock.After did not fire")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnWriteMap 是写时复制的并发安全 map，读操作无锁，每次写操作都会复制整个 map，适用于读多写少的场景，例如配置，零值可直接使用
+// CopyOnWriteMap is a concurrency-safe copy-on-write map, reads are lock-free and every write copies the whole map.
+// It suits read-mostly data such as configuration. The zero value is ready to use.
+type CopyOnWriteMap[K comparable, V any] struct {
+	p atomic.Pointer[map[K]V]
+	// mu 串行化写操作
+	mu sync.Mutex
+}
+
+// NewCopyOnWriteMap 创建一个包含 m 中所有键值对的 CopyOnWriteMap，m 会被复制
+// NewCopyOnWriteMap creates a CopyOnWriteMap with all the entries of m, which is copied.
+func NewCopyOnWriteMap[K comparable, V any](m map[K]V) *CopyOnWriteMap[K, V] {
+	c := &CopyOnWriteMap[K, V]{}
+	c.Replace(m)
+	return c
+}
+
+// Load 返回 key 对应的值以及 key 是否存在
+// Load returns the value of key and whether key exists.
+func (c *CopyOnWriteMap[K, V]) Load(key K) (V, bool) {
+	val, ok := c.load()[key]
+	return val, ok
+}
+
+// Store 设置 key 对应的值
+// Store sets the value of key.
+func (c *CopyOnWriteMap[K, V]) Store(key K, val V) {
+	c.Update(func(m map[K]V) {
+		m[key] = val
+	})
+}
+
+// Delete 删除 key
+// Delete deletes key.
+func (c *CopyOnWriteMap[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.load()[key]; !ok {
+		return
+	}
+	m := maps.Clone(c.load())
+	delete(m, key)
+	c.p.Store(&m)
+}
+
+// Replace 将整个 map 替换为 m 的副本
+// Replace replaces the whole map with a copy of m.
+func (c *CopyOnWriteMap[K, V]) Replace(m map[K]V) {
+	m = maps.Clone(m)
+	if m == nil {
+		m = make(map[K]V)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.Store(&m)
+}
+
+// Update 在当前 map 的副本上执行 fn，并用修改后的副本替换当前 map，fn 中的所有修改对读操作同时可见
+// Update runs fn on a copy of the current map and replaces the current map with the modified copy,
+// so all the modifications in fn become visible to readers at once.
+func (c *CopyOnWriteMap[K, V]) Update(fn func(m map[K]V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := maps.Clone(c.load())
+	if m == nil {
+		m = make(map[K]V)
+	}
+	fn(m)
+	c.p.Store(&m)
+}
+
+// Range 遍历当前 map 的快照，fn 返回 false 时停止遍历，遍历期间的写操作不影响本次遍历
+// Range iterates over a snapshot of the current map and stops when fn returns false.
+// Writes during the iteration do not affect it.
+func (c *CopyOnWriteMap[K, V]) Range(fn func(key K, val V) bool) {
+	for key, val := range c.load() {
+		if !fn(key, val) {
+			return
+		}
+	}
+}
+
+// Snapshot 返回当前 map 的副本
+// Snapshot returns a copy of the current map.
+func (c *CopyOnWriteMap[K, V]) Snapshot() map[K]V {
+	m := maps.Clone(c.load())
+	if m == nil {
+		m = make(map[K]V)
+	}
+	return m
+}
+
+// Len 返回键值对的数量
+// Len returns the number of entries.
+func (c *CopyOnWriteMap[K, V]) Len() int {
+	return len(c.load())
+}
+
+// load 返回当前的 map，该 map 不能被修改
+func (c *CopyOnWriteMap[K, V]) load() map[K]V {
+	if p := c.p.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyOnWriteMap(t *testing.T) {
+	var m CopyOnWriteMap[string, int]
+	_, ok := m.Load("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+	assert.Equal(t, map[string]int{}, m.Snapshot())
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	val, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	assert.Equal(t, 2, m.Len())
+
+	// 快照不受之后的写操作影响
+	snapshot := m.Snapshot()
+	m.Delete("a")
+	m.Delete("c")
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, snapshot)
+	assert.Equal(t, map[string]int{"b": 2}, m.Snapshot())
+
+	m.Update(func(data map[string]int) {
+		data["c"] = 3
+		delete(data, "b")
+	})
+	assert.Equal(t, map[string]int{"c": 3}, m.Snapshot())
+
+	src := map[string]int{"x": 1}
+	m.Replace(src)
+	src["y"] = 2
+	assert.Equal(t, map[string]int{"x": 1}, m.Snapshot())
+	m.Replace(nil)
+	assert.Equal(t, 0, m.Len())
+	m.Store("z", 1)
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestCopyOnWriteMap_Range(t *testing.T) {
+	m := NewCopyOnWriteMap(map[int]int{1: 1, 2: 2, 3: 3})
+	got := make(map[int]int)
+	m.Range(func(key, val int) bool {
+		// 遍历期间的写操作不影响本次遍历
+		m.Store(key+10, val)
+		got[key] = val
+		return true
+	})
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 3}, got)
+	assert.Equal(t, 6, m.Len())
+
+	count := 0
+	m.Range(func(key, val int) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
+func TestCopyOnWriteMap_Concurrent(t *testing.T) {
+	var m CopyOnWriteMap[int, int]
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Store(i*100+j, j)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Range(func(key, val int) bool {
+					assert.Equal(t, key%100, val)
+					return true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, m.Len())
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync"
+	"time"
+)
+
+// DebounceOption 是 Debouncer 的可选配置
+// DebounceOption configures a Debouncer.
+type DebounceOption func(o *debounceOptions)
+
+type debounceOptions struct {
+	leading  bool
+	trailing bool
+	maxWait  time.Duration
+	clock    Clock
+}
+
+// WithLeadingEdge 设置是否在一组连续调用的开始时立即执行，默认为 false
+// WithLeadingEdge sets whether to run at the start of a burst of calls, false by default.
+func WithLeadingEdge(enabled bool) DebounceOption {
+	return func(o *debounceOptions) {
+		o.leading = enabled
+	}
+}
+
+// WithTrailingEdge 设置是否在一组连续调用结束 wait 时间后执行，默认为 true
+// WithTrailingEdge sets whether to run when wait has elapsed since the end of a burst of calls, true by default.
+func WithTrailingEdge(enabled bool) DebounceOption {
+	return func(o *debounceOptions) {
+		o.trailing = enabled
+	}
+}
+
+// WithMaxWait 设置距离上一次执行（或该组调用开始）最长等待的时间，避免持续的调用导致一直不执行：
+// 达到该时间时，启用 trailing edge 则执行尚未执行的调用，启用 leading edge 则下一次调用会立即执行；d 小于等于 0 表示不限制
+// WithMaxWait sets the maximum time to wait since the last run (or the start of the burst), so that continuous calls do not postpone the run forever.
+// Once it elapses, the pending call runs if the trailing edge is enabled, and the next call runs immediately if the leading edge is enabled.
+// d <= 0 means no limit.
+func WithMaxWait(d time.Duration) DebounceOption {
+	return func(o *debounceOptions) {
+		o.maxWait = d
+	}
+}
+
+// WithDebounceClock 设置使用的时钟，默认为 SystemClock
+// WithDebounceClock sets the clock to use, SystemClock by default.
+func WithDebounceClock(clock Clock) DebounceOption {
+	return func(o *debounceOptions) {
+		o.clock = orSystemClock(clock)
+	}
+}
+
+// Debouncer 将一组连续的调用合并为一次执行，由 Debounce 和 Throttle 创建
+// fn 不会被并发执行，定时触发的执行在后台的 goroutine 中进行
+// Debouncer coalesces a burst of calls into a single run, it is created by Debounce and Throttle.
+// fn is never run concurrently, and the timed runs happen in a background goroutine.
+type Debouncer struct {
+	fn    func()
+	wait  time.Duration
+	opts  debounceOptions
+	runMu sync.Mutex
+
+	mu sync.Mutex
+	// active 表示一组连续调用正在进行中，generation 在每组调用开始或被取消时递增，用于让过期的定时 goroutine 退出
+	active     bool
+	generation uint64
+	pending    bool
+	lastCall   time.Time
+	lastRun    time.Time
+}
+
+// Debounce 返回一个 Debouncer，其 Call 方法在最后一次调用 wait 时间后才执行 fn，期间的调用会被合并
+// Debounce returns a Debouncer whose Call method runs fn after wait has elapsed since the last call, the calls in between are coalesced.
+func Debounce(fn func(), wait time.Duration, opts ...DebounceOption) *Debouncer {
+	o := debounceOptions{
+		trailing: true,
+		clock:    SystemClock,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Debouncer{
+		fn:   fn,
+		wait: wait,
+		opts: o,
+	}
+}
+
+// Throttle 返回一个 Debouncer，其 Call 方法在每个 interval 内最多执行一次 fn：第一次调用立即执行，期间的其他调用合并到 interval 结束时执行
+// Throttle returns a Debouncer whose Call method runs fn at most once per interval:
+// the first call runs immediately and the other calls in the interval are coalesced into a run at the end of it.
+func Throttle(fn func(), interval time.Duration, opts ...DebounceOption) *Debouncer {
+	return Debounce(fn, interval, append([]DebounceOption{
+		WithLeadingEdge(true),
+		WithMaxWait(interval),
+	}, opts...)...)
+}
+
+// Call 记录一次调用，根据配置立即或稍后执行 fn
+// Call records a call and runs fn immediately or later as configured.
+func (d *Debouncer) Call() {
+	d.mu.Lock()
+	now := d.opts.clock.Now()
+	d.lastCall = now
+	if !d.active {
+		d.active = true
+		d.generation++
+		d.lastRun = now
+		go d.timer(d.generation)
+		if d.opts.leading {
+			d.mu.Unlock()
+			d.run()
+			return
+		}
+	} else if d.opts.leading && d.opts.maxWait > 0 && !now.Before(d.lastRun.Add(d.opts.maxWait)) {
+		// 距离上一次执行已经达到最长等待时间，立即执行并合并尚未执行的调用
+		d.pending = false
+		d.lastRun = now
+		d.mu.Unlock()
+		d.run()
+		return
+	}
+	d.pending = true
+	d.mu.Unlock()
+}
+
+// Flush 立即执行尚未执行的调用，没有时不做任何事
+// Flush runs the pending call right away, it does nothing if there is none.
+func (d *Debouncer) Flush() {
+	d.mu.Lock()
+	pending := d.pending
+	d.reset()
+	d.mu.Unlock()
+	if pending {
+		d.run()
+	}
+}
+
+// Cancel 取消尚未执行的调用
+// Cancel cancels the pending call.
+func (d *Debouncer) Cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reset()
+}
+
+// timer 等待到下一次需要执行或这组调用结束的时间
+func (d *Debouncer) timer(generation uint64) {
+	for {
+		d.mu.Lock()
+		if d.generation != generation {
+			d.mu.Unlock()
+			return
+		}
+		now := d.opts.clock.Now()
+		deadline := d.lastCall.Add(d.wait)
+		maxDeadline := d.lastRun.Add(d.opts.maxWait)
+		if d.pending && d.opts.maxWait > 0 && !now.Before(maxDeadline) {
+			// 达到最长等待时间，这组调用继续
+			d.pending = false
+			if !d.opts.trailing {
+				d.mu.Unlock()
+				continue
+			}
+			d.lastRun = now
+			d.mu.Unlock()
+			d.run()
+			continue
+		}
+		if !now.Before(deadline) {
+			run := d.pending && d.opts.trailing
+			d.reset()
+			d.mu.Unlock()
+			if run {
+				d.run()
+			}
+			return
+		}
+		if d.pending && d.opts.maxWait > 0 && maxDeadline.Before(deadline) {
+			deadline = maxDeadline
+		}
+		d.mu.Unlock()
+		<-d.opts.clock.After(deadline.Sub(now))
+	}
+}
+
+// reset 结束当前这组调用，调用方需持有 d.mu
+func (d *Debouncer) reset() {
+	d.active = false
+	d.pending = false
+	d.generation++
+}
+
+func (d *Debouncer) run() {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	d.fn()
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebounce(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var count atomic.Int64
+	d := Debounce(func() {
+		count.Add(1)
+	}, time.Second, WithDebounceClock(clock))
+
+	d.Call()
+	advanceWhenWaiting(t, clock, 500*time.Millisecond)
+	d.Call()
+	advanceWhenWaiting(t, clock, 500*time.Millisecond)
+	// 距离最后一次调用还不到 wait
+	advanceWhenWaiting(t, clock, 400*time.Millisecond)
+	assert.Equal(t, int64(0), count.Load())
+	advanceWhenWaiting(t, clock, 100*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return count.Load() == 1
+	}, time.Second, time.Millisecond)
+	waitDebounceIdle(t, d)
+}
+
+func TestDebounce_LeadingEdge(t *testing.T) {
+	testCases := []struct {
+		name      string
+		trailing  bool
+		wantCount int64
+	}{
+		{
+			name:      "只在开始时执行",
+			wantCount: 1,
+		},
+		{
+			name:      "开始和结束时都执行",
+			trailing:  true,
+			wantCount: 2,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			var count atomic.Int64
+			d := Debounce(func() {
+				count.Add(1)
+			}, time.Second, WithDebounceClock(clock), WithLeadingEdge(true), WithTrailingEdge(tt.trailing))
+
+			d.Call()
+			assert.Equal(t, int64(1), count.Load())
+			d.Call()
+			d.Call()
+			assert.Equal(t, int64(1), count.Load())
+			advanceWhenWaiting(t, clock, time.Second)
+			waitDebounceIdle(t, d)
+			assert.Equal(t, tt.wantCount, count.Load())
+
+			// 新的一组调用
+			d.Call()
+			assert.Equal(t, tt.wantCount+1, count.Load())
+		})
+	}
+}
+
+func TestDebounce_MaxWait(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var count atomic.Int64
+	d := Debounce(func() {
+		count.Add(1)
+	}, time.Second, WithDebounceClock(clock), WithMaxWait(2*time.Second))
+
+	// 每 500ms 调用一次，达到最长等待时间时执行
+	for i := 0; i < 4; i++ {
+		d.Call()
+		advanceWhenWaiting(t, clock, 500*time.Millisecond)
+	}
+	assert.Eventually(t, func() bool {
+		return count.Load() == 1
+	}, time.Second, time.Millisecond)
+	// 最后一次调用已经执行，这组调用结束时不再执行
+	advanceWhenWaiting(t, clock, 500*time.Millisecond)
+	waitDebounceIdle(t, d)
+	assert.Equal(t, int64(1), count.Load())
+}
+
+func TestDebounce_FlushAndCancel(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var count atomic.Int64
+	d := Debounce(func() {
+		count.Add(1)
+	}, time.Second, WithDebounceClock(clock))
+
+	d.Flush()
+	assert.Equal(t, int64(0), count.Load())
+	d.Call()
+	d.Flush()
+	assert.Equal(t, int64(1), count.Load())
+
+	d.Call()
+	d.Cancel()
+	clock.Advance(time.Second)
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 0
+	}, time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	assert.Equal(t, int64(1), count.Load())
+}
+
+func TestThrottle(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var count atomic.Int64
+	d := Throttle(func() {
+		count.Add(1)
+	}, time.Second, WithDebounceClock(clock))
+
+	d.Call()
+	assert.Equal(t, int64(1), count.Load())
+	advanceWhenWaiting(t, clock, 300*time.Millisecond)
+	d.Call()
+	d.Call()
+	// interval 结束时执行期间的调用
+	advanceWhenWaiting(t, clock, 700*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return count.Load() == 2
+	}, time.Second, time.Millisecond)
+	advanceWhenWaiting(t, clock, 300*time.Millisecond)
+	waitDebounceIdle(t, d)
+	assert.Equal(t, int64(2), count.Load())
+
+	d.Call()
+	assert.Equal(t, int64(3), count.Load())
+}
+
+func TestThrottle_WithoutTrailingEdge(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var count atomic.Int64
+	d := Throttle(func() {
+		count.Add(1)
+	}, time.Second, WithDebounceClock(clock), WithTrailingEdge(false))
+
+	d.Call()
+	advanceWhenWaiting(t, clock, 500*time.Millisecond)
+	d.Call()
+	advanceWhenWaiting(t, clock, 500*time.Millisecond)
+	assert.Equal(t, int64(1), count.Load())
+	// 持续调用时每个 interval 执行一次
+	d.Call()
+	assert.Equal(t, int64(2), count.Load())
+	d.Call()
+	assert.Equal(t, int64(2), count.Load())
+}
+
+// advanceWhenWaiting 等待定时的 goroutine 开始等待后推进时钟
+func advanceWhenWaiting(t *testing.T, clock *FakeClock, d time.Duration) {
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	clock.Advance(d)
+}
+
+// waitDebounceIdle 等待当前这组调用结束
+func waitDebounceIdle(t *testing.T, d *Debouncer) {
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return !d.active
+	}, time.Second, time.Millisecond)
+}
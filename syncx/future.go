@@ -0,0 +1,195 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoFutures 表示传给 Any 或 Race 的 Future 为空
+// ErrNoFutures is returned by Any and Race when no future is given.
+var ErrNoFutures = errors.New("syncx: no futures")
+
+// Future 表示一个异步计算的结果，由 Go 等函数创建
+// Future is the result of an asynchronous computation, it is created by Go and the other functions.
+type Future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Go 在新的 goroutine 中执行 fn，并返回表示其结果的 Future，fn 中发生的 panic 会被转换为 *PanicError
+// Go runs fn in a new goroutine and returns a Future of its result, a panic in fn is converted to a *PanicError.
+func Go[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := newFuture[T]()
+	go func() {
+		var val T
+		err := callSafely(func() (err error) {
+			val, err = fn(ctx)
+			return err
+		})
+		f.complete(val, err)
+	}()
+	return f
+}
+
+// Await 等待计算完成并返回其结果，ctx 先结束时返回 ctx.Err()，但不会取消计算；计算已经完成时总是返回其结果
+// Await waits for the computation to complete and returns its result.
+// It returns ctx.Err() if ctx is done first, which does not cancel the computation.
+// The result is always returned if the computation has already completed.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	default:
+	}
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done 返回一个在计算完成时关闭的 channel
+// Done returns a channel that is closed when the computation completes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Then 在 f 成功完成后以其结果执行 fn，并返回表示 fn 结果的 Future；f 失败时不执行 fn，返回的 Future 以相同的错误失败
+// f 完成前 ctx 结束时不执行 fn，返回的 Future 以 ctx.Err() 失败
+// Then runs fn with the result of f after f succeeds and returns a Future of the result of fn.
+// If f fails, fn is not run and the returned Future fails with the same error.
+// If ctx is done before f completes, fn is not run and the returned Future fails with ctx.Err().
+func Then[T, U any](ctx context.Context, f *Future[T], fn func(ctx context.Context, val T) (U, error)) *Future[U] {
+	return Go(ctx, func(ctx context.Context) (U, error) {
+		// Await 在 f 已经完成时总是返回其结果，因此只有 f 完成前 ctx 结束才会放弃
+		val, err := f.Await(ctx)
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return fn(ctx, val)
+	})
+}
+
+// All 返回一个在所有 Future 都成功后以按顺序排列的结果完成的 Future，任一 Future 失败时立即以该错误失败
+// All returns a Future that completes with the results in order after all the futures succeed,
+// or fails with the error of the first future that fails.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	res := newFuture[[]T]()
+	go func() {
+		vals := make([]T, len(fs))
+		results := collectResults(fs)
+		for range fs {
+			r := <-results
+			if r.err != nil {
+				res.complete(nil, r.err)
+				return
+			}
+			vals[r.idx] = r.val
+		}
+		res.complete(vals, nil)
+	}()
+	return res
+}
+
+// Any 返回一个以第一个成功的 Future 的结果完成的 Future，所有 Future 都失败时以 errors.Join 合并后的所有错误失败
+// Any returns a Future that completes with the result of the first future that succeeds,
+// or fails with all the errors combined by errors.Join if all of them fail.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	res := newFuture[T]()
+	if len(fs) == 0 {
+		var zero T
+		res.complete(zero, ErrNoFutures)
+		return res
+	}
+	go func() {
+		errs := make([]error, len(fs))
+		results := collectResults(fs)
+		for range fs {
+			r := <-results
+			if r.err == nil {
+				res.complete(r.val, nil)
+				return
+			}
+			errs[r.idx] = r.err
+		}
+		var zero T
+		res.complete(zero, errors.Join(errs...))
+	}()
+	return res
+}
+
+// Race 返回一个以第一个完成的 Future 的结果完成的 Future，无论其成功与否
+// Race returns a Future that completes with the result of the first future that completes, whether it succeeds or not.
+func Race[T any](fs ...*Future[T]) *Future[T] {
+	res := newFuture[T]()
+	if len(fs) == 0 {
+		var zero T
+		res.complete(zero, ErrNoFutures)
+		return res
+	}
+	go func() {
+		r := <-collectResults(fs)
+		res.complete(r.val, r.err)
+	}()
+	return res
+}
+
+// WithTimeout 返回一个与 f 结果相同的 Future，f 在 d 时间内未完成时以 context.DeadlineExceeded 失败，但不会取消 f 的计算
+// WithTimeout returns a Future with the same result as f, which fails with context.DeadlineExceeded if f does not complete within d.
+// The computation of f is not canceled.
+func WithTimeout[T any](f *Future[T], d time.Duration) *Future[T] {
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return f.Await(ctx)
+	})
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{
+		done: make(chan struct{}),
+	}
+}
+
+// complete 设置结果并唤醒等待者，只能调用一次
+func (f *Future[T]) complete(val T, err error) {
+	f.val, f.err = val, err
+	close(f.done)
+}
+
+type futureResult[T any] struct {
+	idx int
+	val T
+	err error
+}
+
+// collectResults 按完成的顺序发送各个 Future 的结果，channel 有足够的缓冲，不读取也不会导致 goroutine 泄漏
+func collectResults[T any](fs []*Future[T]) <-chan futureResult[T] {
+	results := make(chan futureResult[T], len(fs))
+	for i, f := range fs {
+		go func(idx int, f *Future[T]) {
+			<-f.done
+			results <- futureResult[T]{idx: idx, val: f.val, err: f.err}
+		}(i, f)
+	}
+	return results
+}
@@ -0,0 +1,238 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuture(t *testing.T) {
+	mockErr := errors.New("mock error")
+	testCases := []struct {
+		name    string
+		fn      func(ctx context.Context) (int, error)
+		wantVal int
+		wantErr func(t *testing.T, err error)
+	}{
+		{
+			name: "成功",
+			fn: func(ctx context.Context) (int, error) {
+				return 1, nil
+			},
+			wantVal: 1,
+			wantErr: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "失败",
+			fn: func(ctx context.Context) (int, error) {
+				return 0, mockErr
+			},
+			wantErr: func(t *testing.T, err error) {
+				assert.Equal(t, mockErr, err)
+			},
+		},
+		{
+			name: "panic",
+			fn: func(ctx context.Context) (int, error) {
+				panic("boom")
+			},
+			wantErr: func(t *testing.T, err error) {
+				var panicErr *PanicError
+				require.ErrorAs(t, err, &panicErr)
+				assert.Equal(t, "boom", panicErr.Value)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Go(context.Background(), tt.fn)
+			val, err := f.Await(context.Background())
+			assert.Equal(t, tt.wantVal, val)
+			tt.wantErr(t, err)
+			// 多次 Await 返回相同的结果
+			val, err = f.Await(context.Background())
+			assert.Equal(t, tt.wantVal, val)
+			tt.wantErr(t, err)
+		})
+	}
+}
+
+func TestFuture_AwaitContext(t *testing.T) {
+	release := make(chan struct{})
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.Await(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	close(release)
+	<-f.Done()
+	val, err := f.Await(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+}
+
+func TestThen(t *testing.T) {
+	mockErr := errors.New("mock error")
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	val, err := Then(context.Background(), f, func(ctx context.Context, val int) (string, error) {
+		return strconv.Itoa(val), nil
+	}).Await(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "42", val)
+
+	called := false
+	failed := Go(context.Background(), func(ctx context.Context) (int, error) {
+		return 0, mockErr
+	})
+	_, err = Then(context.Background(), failed, func(ctx context.Context, val int) (string, error) {
+		called = true
+		return "", nil
+	}).Await(context.Background())
+	assert.Equal(t, mockErr, err)
+	assert.False(t, called)
+
+	// f 完成前 ctx 结束时不再等待 f
+	ctx, cancel := context.WithCancel(context.Background())
+	pending := newFuture[int]()
+	then := Then(ctx, pending, func(ctx context.Context, val int) (string, error) {
+		called = true
+		return "", nil
+	})
+	cancel()
+	_, err = then.Await(context.Background())
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, called)
+
+	// f 已经完成时即使 ctx 已结束也总是执行 fn
+	completed := Go(context.Background(), func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	_, err = completed.Await(context.Background())
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		val, err = Then(ctx, completed, func(ctx context.Context, val int) (string, error) {
+			return strconv.Itoa(val), nil
+		}).Await(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "42", val)
+	}
+}
+
+func TestAll(t *testing.T) {
+	mockErr := errors.New("mock error")
+
+	val, err := All(
+		delayedFuture(30*time.Millisecond, 1, nil),
+		delayedFuture(10*time.Millisecond, 2, nil),
+		delayedFuture(20*time.Millisecond, 3, nil),
+	).Await(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, val)
+
+	// 任一失败时立即失败，不等待其他 Future
+	never := make(chan struct{})
+	defer close(never)
+	_, err = All(
+		Go(context.Background(), func(ctx context.Context) (int, error) {
+			<-never
+			return 0, nil
+		}),
+		delayedFuture(0, 0, mockErr),
+	).Await(context.Background())
+	assert.Equal(t, mockErr, err)
+
+	val, err = All[int]().Await(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, val)
+}
+
+func TestAny(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+
+	val, err := Any(
+		delayedFuture(0, 0, err1),
+		delayedFuture(20*time.Millisecond, 2, nil),
+		delayedFuture(10*time.Millisecond, 3, nil),
+	).Await(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+
+	_, err = Any(
+		delayedFuture(10*time.Millisecond, 0, err1),
+		delayedFuture(0, 0, err2),
+	).Await(context.Background())
+	assert.Equal(t, errors.Join(err1, err2), err)
+
+	_, err = Any[int]().Await(context.Background())
+	assert.Equal(t, ErrNoFutures, err)
+}
+
+func TestRace(t *testing.T) {
+	mockErr := errors.New("mock error")
+
+	val, err := Race(
+		delayedFuture(20*time.Millisecond, 1, nil),
+		delayedFuture(0, 2, nil),
+	).Await(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, val)
+
+	_, err = Race(
+		delayedFuture(20*time.Millisecond, 1, nil),
+		delayedFuture(0, 0, mockErr),
+	).Await(context.Background())
+	assert.Equal(t, mockErr, err)
+
+	_, err = Race[int]().Await(context.Background())
+	assert.Equal(t, ErrNoFutures, err)
+}
+
+func TestWithTimeout(t *testing.T) {
+	val, err := WithTimeout(delayedFuture(0, 1, nil), time.Second).Await(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := Go(context.Background(), func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	_, err = WithTimeout(slow, 10*time.Millisecond).Await(context.Background())
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func delayedFuture(d time.Duration, val int, err error) *Future[int] {
+	return Go(context.Background(), func(ctx context.Context) (int, error) {
+		time.Sleep(d)
+		return val, err
+	})
+}
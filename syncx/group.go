@@ -0,0 +1,233 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// GroupOption 是 Group 的可选配置
+// GroupOption configures a Group.
+type GroupOption func(g *Group)
+
+// WithCollectAllErrors 让 Group 收集所有任务的错误，Wait 返回由 errors.Join 合并后的错误，且不会因为某个任务失败而取消 ctx
+// WithCollectAllErrors makes the Group collect the errors of all tasks, Wait returns them combined by errors.Join,
+// and the ctx is not canceled when a single task fails.
+func WithCollectAllErrors() GroupOption {
+	return func(g *Group) {
+		g.collectAll = true
+	}
+}
+
+// Group 并发执行一组任务并等待它们完成，类似 errgroup.Group，支持限制并发数、收集所有错误以及将任务中的 panic 转换为 *PanicError，零值可直接使用
+// Group runs a set of tasks concurrently and waits for them, like errgroup.Group. It supports limiting the concurrency,
+// collecting all errors and converting a panic in a task into a *PanicError. The zero value is ready to use.
+type Group struct {
+	cancel     context.CancelCauseFunc
+	collectAll bool
+	wg         sync.WaitGroup
+	sem        chan struct{}
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewGroup 创建一个 Group 以及从 ctx 派生的 context，该 context 在第一个任务失败或 Wait 返回时被取消
+// NewGroup creates a Group and a context derived from ctx, which is canceled when the first task fails or Wait returns.
+func NewGroup(ctx context.Context, opts ...GroupOption) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{
+		cancel: cancel,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, ctx
+}
+
+// SetLimit 将同时执行的任务数限制为 n，n 小于 0 表示不限制；在有任务执行时修改限制会 panic
+// SetLimit limits the number of concurrently running tasks to n, a negative n means no limit.
+// It panics if called while any task is running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("syncx: modify limit while %d tasks are still running", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go 在新的 goroutine 中执行 fn，达到并发限制时阻塞，直到有任务完成
+// Go runs fn in a new goroutine, it blocks while the concurrency limit is reached until a task finishes.
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn)
+}
+
+// TryGo 在未达到并发限制时在新的 goroutine 中执行 fn，返回是否执行
+// TryGo runs fn in a new goroutine if the concurrency limit is not reached and reports whether it did.
+func (g *Group) TryGo(fn func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+// Wait 等待所有任务完成，返回第一个任务错误；使用 WithCollectAllErrors 时返回所有错误合并后的结果
+// Wait waits for all tasks to finish and returns the first error,
+// or all the errors combined by errors.Join when WithCollectAllErrors is used.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var err error
+	if g.collectAll {
+		err = errors.Join(g.errs...)
+	} else if len(g.errs) > 0 {
+		err = g.errs[0]
+	}
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+	return err
+}
+
+func (g *Group) start(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := callSafely(fn); err != nil {
+			g.record(err)
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+func (g *Group) record(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.collectAll {
+		g.errs = append(g.errs, err)
+		return
+	}
+	if len(g.errs) > 0 {
+		return
+	}
+	g.errs = append(g.errs, err)
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+}
+
+// GroupResult 是收集任务返回值的 Group，返回值按照任务的提交顺序排列，零值可直接使用
+// GroupResult is a Group that collects the values returned by the tasks in submission order. The zero value is ready to use.
+type GroupResult[T any] struct {
+	g Group
+
+	mu      sync.Mutex
+	results []T
+}
+
+// NewGroupResult 创建一个 GroupResult 以及从 ctx 派生的 context，context 的取消规则与 NewGroup 相同
+// NewGroupResult creates a GroupResult and a context derived from ctx, which is canceled in the same way as NewGroup.
+func NewGroupResult[T any](ctx context.Context, opts ...GroupOption) (*GroupResult[T], context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	r := &GroupResult[T]{
+		g: Group{
+			cancel: cancel,
+		},
+	}
+	for _, opt := range opts {
+		opt(&r.g)
+	}
+	return r, ctx
+}
+
+// SetLimit 同 Group.SetLimit
+// SetLimit is the same as Group.SetLimit.
+func (r *GroupResult[T]) SetLimit(n int) {
+	r.g.SetLimit(n)
+}
+
+// Go 在新的 goroutine 中执行 fn，并在提交顺序对应的位置记录其返回值
+// Go runs fn in a new goroutine and records its value at the position of the submission order.
+func (r *GroupResult[T]) Go(fn func() (T, error)) {
+	r.mu.Lock()
+	idx := r.reserve()
+	r.mu.Unlock()
+	r.g.Go(r.task(idx, fn))
+}
+
+// TryGo 在未达到并发限制时在新的 goroutine 中执行 fn，返回是否执行，未执行的任务不占用结果中的位置
+// TryGo runs fn in a new goroutine if the concurrency limit is not reached and reports whether it did.
+// A task that is not run takes no position in the results.
+func (r *GroupResult[T]) TryGo(fn func() (T, error)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	idx := r.reserve()
+	if !r.g.TryGo(r.task(idx, fn)) {
+		r.results = r.results[:idx]
+		return false
+	}
+	return true
+}
+
+// Wait 等待所有任务完成，返回按提交顺序排列的返回值以及与 Group.Wait 相同的错误，失败的任务对应的位置为零值
+// Wait waits for all tasks to finish and returns the values in submission order along with the same error as Group.Wait.
+// The position of a failed task holds the zero value.
+func (r *GroupResult[T]) Wait() ([]T, error) {
+	err := r.g.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.results, err
+}
+
+// reserve 为新任务预留结果中的位置，调用方需持有 r.mu
+func (r *GroupResult[T]) reserve() int {
+	var zero T
+	r.results = append(r.results, zero)
+	return len(r.results) - 1
+}
+
+func (r *GroupResult[T]) task(idx int, fn func() (T, error)) func() error {
+	return func() error {
+		val, err := fn()
+		if err != nil {
+			return err
+		}
+		r.mu.Lock()
+		r.results[idx] = val
+		r.mu.Unlock()
+		return nil
+	}
+}
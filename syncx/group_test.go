@@ -0,0 +1,203 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_Wait(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	testCases := []struct {
+		name    string
+		opts    []GroupOption
+		tasks   []func() error
+		wantErr func(t *testing.T, err error)
+	}{
+		{
+			name: "全部成功",
+			tasks: []func() error{
+				func() error { return nil },
+				func() error { return nil },
+			},
+			wantErr: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "返回第一个错误",
+			tasks: []func() error{
+				func() error { return err1 },
+				func() error {
+					time.Sleep(10 * time.Millisecond)
+					return err2
+				},
+			},
+			wantErr: func(t *testing.T, err error) {
+				assert.Equal(t, err1, err)
+			},
+		},
+		{
+			name: "收集所有错误",
+			opts: []GroupOption{WithCollectAllErrors()},
+			tasks: []func() error{
+				func() error { return err1 },
+				func() error { return nil },
+				func() error { return err2 },
+			},
+			wantErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, err1)
+				assert.ErrorIs(t, err, err2)
+			},
+		},
+		{
+			name: "panic 被转换为错误",
+			tasks: []func() error{
+				func() error { panic("boom") },
+			},
+			wantErr: func(t *testing.T, err error) {
+				var panicErr *PanicError
+				require.ErrorAs(t, err, &panicErr)
+				assert.Equal(t, "boom", panicErr.Value)
+				assert.NotEmpty(t, panicErr.Stack)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _ := NewGroup(context.Background(), tt.opts...)
+			for _, task := range tt.tasks {
+				g.Go(task)
+			}
+			tt.wantErr(t, g.Wait())
+		})
+	}
+}
+
+func TestGroup_Context(t *testing.T) {
+	err := errors.New("mock error")
+
+	g, ctx := NewGroup(context.Background())
+	g.Go(func() error { return err })
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(t, err, g.Wait())
+	assert.Equal(t, err, context.Cause(ctx))
+
+	// 收集所有错误时，任务失败不会取消 ctx
+	g, ctx = NewGroup(context.Background(), WithCollectAllErrors())
+	g.Go(func() error { return err })
+	g.Go(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return ctx.Err()
+	})
+	assert.Equal(t, errors.Join(err), g.Wait())
+	assert.Error(t, ctx.Err())
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+
+	var (
+		current atomic.Int64
+		peak    atomic.Int64
+	)
+	for i := 0; i < 20; i++ {
+		g.Go(func() error {
+			cur := current.Add(1)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			current.Add(-1)
+			return nil
+		})
+	}
+	assert.NoError(t, g.Wait())
+	assert.Equal(t, int64(2), peak.Load())
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	var g Group
+	g.SetLimit(1)
+	release := make(chan struct{})
+	assert.True(t, g.TryGo(func() error {
+		<-release
+		return nil
+	}))
+	assert.False(t, g.TryGo(func() error { return nil }))
+	assert.Panics(t, func() {
+		g.SetLimit(2)
+	})
+	close(release)
+	assert.NoError(t, g.Wait())
+	assert.True(t, g.TryGo(func() error { return nil }))
+	assert.NoError(t, g.Wait())
+}
+
+func TestGroupResult(t *testing.T) {
+	mockErr := errors.New("mock error")
+
+	r, _ := NewGroupResult[int](context.Background(), WithCollectAllErrors())
+	r.SetLimit(3)
+	for i := 0; i < 10; i++ {
+		i := i
+		r.Go(func() (int, error) {
+			// 让后提交的任务先完成，以验证结果按提交顺序排列
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			if i == 5 {
+				return i, mockErr
+			}
+			return i * i, nil
+		})
+	}
+	results, err := r.Wait()
+	assert.ErrorIs(t, err, mockErr)
+	assert.Equal(t, []int{0, 1, 4, 9, 16, 0, 36, 49, 64, 81}, results)
+}
+
+func TestGroupResult_TryGo(t *testing.T) {
+	var r GroupResult[string]
+	r.SetLimit(1)
+	release := make(chan struct{})
+	assert.True(t, r.TryGo(func() (string, error) {
+		<-release
+		return "a", nil
+	}))
+	assert.False(t, r.TryGo(func() (string, error) { return "b", nil }))
+	close(release)
+	results, err := r.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, results)
+
+	assert.True(t, r.TryGo(func() (string, error) { return "c", nil }))
+	results, err = r.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, results)
+}
@@ -14,49 +14,373 @@
 
 package syncx
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
 
-type KeyLocker interface {
-	Lock(key string)
-	Unlock(key string)
+var (
+	_ ContextKeyLocker                 = &MapKeyLock{}
+	_ RWKeyLocker                      = &MapKeyLock{}
+	_ RWContextKeyLocker               = &MapKeyLock{}
+	_ GenericRWContextKeyLocker[int64] = &KeyLock[int64]{}
+)
+
+// GenericKeyLocker 是以 K 为 key 的锁
+// GenericKeyLocker locks keys of type K.
+type GenericKeyLocker[K comparable] interface {
+	Lock(key K)
+	Unlock(key K)
+}
+
+// GenericRWKeyLocker 在 GenericKeyLocker 的基础上支持读锁
+// GenericRWKeyLocker extends GenericKeyLocker with read locks.
+type GenericRWKeyLocker[K comparable] interface {
+	GenericKeyLocker[K]
+	RLock(key K)
+	RUnLock(key K)
+}
+
+// GenericContextKeyLocker 在 GenericKeyLocker 的基础上支持放弃等待
+// GenericContextKeyLocker extends GenericKeyLocker with locking that can give up waiting.
+type GenericContextKeyLocker[K comparable] interface {
+	GenericKeyLocker[K]
+	// LockContext 获取 key 的锁，在 ctx 结束前仍未获取到锁时放弃等待并返回 ctx.Err()
+	// LockContext locks the key, or gives up and returns ctx.Err() if ctx is done before the lock is acquired.
+	LockContext(ctx context.Context, key K) error
+	// TryLockTimeout 在 d 时间内尝试获取 key 的锁，返回是否获取成功
+	// TryLockTimeout tries to lock the key within d and reports whether it succeeded.
+	TryLockTimeout(key K, d time.Duration) bool
+}
+
+// GenericRWContextKeyLocker 在 GenericRWKeyLocker 和 GenericContextKeyLocker 的基础上支持可放弃等待的读锁
+// GenericRWContextKeyLocker extends GenericRWKeyLocker and GenericContextKeyLocker with read locking that can give up waiting.
+type GenericRWContextKeyLocker[K comparable] interface {
+	GenericRWKeyLocker[K]
+	GenericContextKeyLocker[K]
+	// RLockContext 获取 key 的读锁，在 ctx 结束前仍未获取到读锁时放弃等待并返回 ctx.Err()
+	// RLockContext read-locks the key, or gives up and returns ctx.Err() if ctx is done before the read lock is acquired.
+	RLockContext(ctx context.Context, key K) error
+	// TryRLockTimeout 在 d 时间内尝试获取 key 的读锁，返回是否获取成功
+	// TryRLockTimeout tries to read-lock the key within d and reports whether it succeeded.
+	TryRLockTimeout(key K, d time.Duration) bool
+}
+
+// KeyLocker 是以 string 为 key 的 GenericKeyLocker
+// KeyLocker is the GenericKeyLocker for string keys.
+type KeyLocker = GenericKeyLocker[string]
+
+// RWKeyLocker 是以 string 为 key 的 GenericRWKeyLocker
+// RWKeyLocker is the GenericRWKeyLocker for string keys.
+type RWKeyLocker = GenericRWKeyLocker[string]
+
+// ContextKeyLocker 是以 string 为 key 的 GenericContextKeyLocker
+// ContextKeyLocker is the GenericContextKeyLocker for string keys.
+type ContextKeyLocker = GenericContextKeyLocker[string]
+
+// RWContextKeyLocker 是以 string 为 key 的 GenericRWContextKeyLocker
+// RWContextKeyLocker is the GenericRWContextKeyLocker for string keys.
+type RWContextKeyLocker = GenericRWContextKeyLocker[string]
+
+var (
+	// ErrUnlockOfUnlocked 表示解锁一个未被持有的 key
+	// ErrUnlockOfUnlocked is returned when unlocking a key that is not locked.
+	ErrUnlockOfUnlocked = errors.New("syncx: unlock of unlocked key")
+	// ErrUnlockWrongMode 表示以错误的模式解锁，例如对持有读锁的 key 调用 Unlock
+	// ErrUnlockWrongMode is returned when unlocking a key with the wrong mode, e.g. calling Unlock on a read-locked key.
+	ErrUnlockWrongMode = errors.New("syncx: unlock with wrong mode")
+)
+
+// KeyLockOption 是 KeyLock 的可选配置
+// KeyLockOption configures a KeyLock.
+type KeyLockOption func(opts *keyLockOptions)
+
+type keyLockOptions struct {
+	debug bool
+}
+
+// WithDebug 开启调试模式，便于在测试中发现误用
+// 调试模式下会记录每个 key 的持有模式和持有者数量，解锁出错时的错误信息中包含这些信息以及等待者的数量，
+// 并且解锁未被持有的 key 会 panic，而不是静默忽略
+// WithDebug enables the debug mode, which helps to catch misuse in tests.
+// In the debug mode, the holding mode and the number of holders of each key are recorded and included in unlock errors
+// together with the number of waiters, and unlocking a key that is not locked panics instead of being silently ignored.
+func WithDebug() KeyLockOption {
+	return func(opts *keyLockOptions) {
+		opts.debug = true
+	}
+}
+
+// KeyLock 为每个 key 维护一把读写锁，并通过引用计数在没有持有者和等待者时回收该 key 的锁
+// 以错误的模式解锁时总是会 panic；解锁未被持有的 key 默认被忽略，开启调试模式后会 panic
+// KeyLock maintains a read-write lock per key, and removes the lock of a key once it has no holder or waiter.
+// Unlocking with the wrong mode always panics; unlocking a key that is not locked is ignored unless the debug mode is enabled.
+type KeyLock[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*refRWMutex
+	opts  keyLockOptions
+}
+
+// MapKeyLock 是以 string 为 key 的 KeyLock
+// MapKeyLock is the KeyLock for string keys.
+type MapKeyLock = KeyLock[string]
+
+// refRWMutex 是带引用计数的读写锁，ref 记录持有者和等待者的数量，由 KeyLock.mu 保护
+type refRWMutex struct {
+	rwMutex
+	ref int
+	// holders 是调试模式下记录的持有者，由 KeyLock.mu 保护
+	holders keyHolders
+}
+
+// keyHolders 记录 key 的持有模式和持有者数量
+type keyHolders struct {
+	writer  bool
+	readers int
+}
+
+func (h keyHolders) count() int {
+	if h.writer {
+		return 1
+	}
+	return h.readers
+}
+
+func (h keyHolders) String() string {
+	switch {
+	case h.writer:
+		return "holders: 1 writer"
+	case h.readers == 1:
+		return "holders: 1 reader"
+	case h.readers > 1:
+		return fmt.Sprintf("holders: %d readers", h.readers)
+	default:
+		return "holders: none"
+	}
+}
+
+func NewKeyLock[K comparable](opts ...KeyLockOption) *KeyLock[K] {
+	l := &KeyLock[K]{
+		locks: make(map[K]*refRWMutex),
+	}
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+	return l
+}
+
+func NewMapKeyLock(opts ...KeyLockOption) *MapKeyLock {
+	return NewKeyLock[string](opts...)
+}
+
+func (l *KeyLock[K]) Lock(key K) {
+	mu := l.acquire(key)
+	mu.Lock()
+	l.hold(mu, true)
+}
+
+func (l *KeyLock[K]) Unlock(key K) {
+	l.mustRelease(l.SafeUnlock(key))
+}
+
+func (l *KeyLock[K]) RLock(key K) {
+	mu := l.acquire(key)
+	mu.RLock()
+	l.hold(mu, false)
+}
+
+func (l *KeyLock[K]) RUnLock(key K) {
+	l.mustRelease(l.SafeRUnLock(key))
+}
+
+// SafeUnlock 释放 key 的写锁，误用时返回错误而不是 panic
+// SafeUnlock unlocks the write lock of the key, and returns an error instead of panicking on misuse.
+func (l *KeyLock[K]) SafeUnlock(key K) error {
+	return l.release(key, func(mu *refRWMutex) error {
+		if err := mu.unlock(); err != nil {
+			return err
+		}
+		if l.opts.debug {
+			mu.holders.writer = false
+		}
+		return nil
+	})
+}
+
+// SafeRUnLock 释放 key 的读锁，误用时返回错误而不是 panic
+// SafeRUnLock unlocks the read lock of the key, and returns an error instead of panicking on misuse.
+func (l *KeyLock[K]) SafeRUnLock(key K) error {
+	return l.release(key, func(mu *refRWMutex) error {
+		if err := mu.rUnlock(); err != nil {
+			return err
+		}
+		if l.opts.debug {
+			mu.holders.readers--
+		}
+		return nil
+	})
+}
+
+// LockGuard 获取 key 的写锁，并返回用于解锁的函数，该函数多次调用时只会解锁一次
+// 用法：unlock := l.LockGuard(key); defer unlock()
+// LockGuard locks the key and returns a function that unlocks it, calling the function more than once unlocks only once.
+func (l *KeyLock[K]) LockGuard(key K) (unlock func()) {
+	l.Lock(key)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.Unlock(key)
+		})
+	}
+}
+
+// RLockGuard 获取 key 的读锁，并返回用于解锁的函数，该函数多次调用时只会解锁一次
+// RLockGuard read-locks the key and returns a function that unlocks it, calling the function more than once unlocks only once.
+func (l *KeyLock[K]) RLockGuard(key K) (unlock func()) {
+	l.RLock(key)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.RUnLock(key)
+		})
+	}
+}
+
+// Locked 判断 key 当前是否被持有写锁或读锁
+// Locked reports whether the key is currently write-locked or read-locked.
+func (l *KeyLock[K]) Locked(key K) bool {
+	l.mu.Lock()
+	mu, ok := l.locks[key]
+	l.mu.Unlock()
+	return ok && mu.locked()
 }
 
-type MapKeyLock struct {
-	locks sync.Map
+func (l *KeyLock[K]) TryLock(key K) bool {
+	mu := l.acquire(key)
+	if mu.TryLock() {
+		l.hold(mu, true)
+		return true
+	}
+	_ = l.release(key, nil)
+	return false
 }
 
-func NewMapKeyLock() *MapKeyLock {
-	return &MapKeyLock{}
+func (l *KeyLock[K]) TryRLock(key K) bool {
+	mu := l.acquire(key)
+	if mu.TryRLock() {
+		l.hold(mu, false)
+		return true
+	}
+	_ = l.release(key, nil)
+	return false
 }
 
-func (l *MapKeyLock) Lock(key string) {
-	mu, _ := l.locks.LoadOrStore(key, &sync.RWMutex{})
-	mu.(*sync.RWMutex).Lock()
+func (l *KeyLock[K]) LockContext(ctx context.Context, key K) error {
+	mu := l.acquire(key)
+	if err := mu.LockContext(ctx); err != nil {
+		_ = l.release(key, nil)
+		return err
+	}
+	l.hold(mu, true)
+	return nil
 }
 
-func (l *MapKeyLock) Unlock(key string) {
-	if mu, ok := l.locks.Load(key); ok {
-		mu.(*sync.RWMutex).Unlock()
+func (l *KeyLock[K]) RLockContext(ctx context.Context, key K) error {
+	mu := l.acquire(key)
+	if err := mu.RLockContext(ctx); err != nil {
+		_ = l.release(key, nil)
+		return err
 	}
+	l.hold(mu, false)
+	return nil
+}
+
+func (l *KeyLock[K]) TryLockTimeout(key K, d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return l.LockContext(ctx, key) == nil
 }
 
-func (l *MapKeyLock) RLock(key string) {
-	mu, _ := l.locks.LoadOrStore(key, &sync.RWMutex{})
-	mu.(*sync.RWMutex).RLock()
+func (l *KeyLock[K]) TryRLockTimeout(key K, d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return l.RLockContext(ctx, key) == nil
 }
 
-func (l *MapKeyLock) RUnLock(key string) {
-	if mu, ok := l.locks.Load(key); ok {
-		mu.(*sync.RWMutex).RUnlock()
+// Len 返回当前被持有或等待中的 key 的数量，可用于监控
+// Len returns the number of keys that are currently held or waited on, which is useful for monitoring.
+func (l *KeyLock[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.locks)
+}
+
+// acquire 获取 key 对应的锁并增加其引用计数，锁不存在时会创建
+func (l *KeyLock[K]) acquire(key K) *refRWMutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks == nil {
+		l.locks = make(map[K]*refRWMutex)
+	}
+	mu, ok := l.locks[key]
+	if !ok {
+		mu = &refRWMutex{}
+		l.locks[key] = mu
 	}
+	mu.ref++
+	return mu
 }
 
-func (l *MapKeyLock) TryLock(key string) bool {
-	mu, _ := l.locks.LoadOrStore(key, &sync.RWMutex{})
-	return mu.(*sync.RWMutex).TryLock()
+// hold 在调试模式下记录 key 的持有者
+func (l *KeyLock[K]) hold(mu *refRWMutex, write bool) {
+	if !l.opts.debug {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if write {
+		mu.holders.writer = true
+	} else {
+		mu.holders.readers++
+	}
 }
 
-func (l *MapKeyLock) TryRLock(key string) bool {
-	mu, _ := l.locks.LoadOrStore(key, &sync.RWMutex{})
-	return mu.(*sync.RWMutex).TryRLock()
+// release 对 key 对应的锁执行 unlock 后减少其引用计数，引用计数为 0 时回收该锁
+// unlock 为 nil 时表示获取锁失败，只减少引用计数
+func (l *KeyLock[K]) release(key K, unlock func(mu *refRWMutex) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	mu, ok := l.locks[key]
+	if !ok {
+		return l.unlockError(ErrUnlockOfUnlocked, key, &refRWMutex{})
+	}
+	if unlock != nil {
+		if err := unlock(mu); err != nil {
+			return l.unlockError(err, key, mu)
+		}
+	}
+	mu.ref--
+	if mu.ref == 0 {
+		delete(l.locks, key)
+	}
+	return nil
+}
+
+// unlockError 为解锁错误附加 key，调试模式下还会附加持有者和等待者的数量，调用方需持有 l.mu
+func (l *KeyLock[K]) unlockError(err error, key K, mu *refRWMutex) error {
+	if !l.opts.debug {
+		return fmt.Errorf("%w, key: %v", err, key)
+	}
+	return fmt.Errorf("%w, key: %v, %s, waiters: %d", err, key, mu.holders, mu.ref-mu.holders.count())
+}
+
+// mustRelease 在解锁出错时 panic，非调试模式下忽略解锁未被持有的 key 的错误
+func (l *KeyLock[K]) mustRelease(err error) {
+	if err == nil || (!l.opts.debug && errors.Is(err, ErrUnlockOfUnlocked)) {
+		return
+	}
+	panic(err)
 }
@@ -15,7 +15,12 @@
 package syncx
 
 import (
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -57,3 +62,341 @@ func TestMapKeyLock_RLock(t *testing.T) {
 
 	lock.RUnLock(key2)
 }
+
+func TestMapKeyLock_Len(t *testing.T) {
+	lock := NewMapKeyLock()
+	assert.Equal(t, 0, lock.Len())
+
+	lock.Lock("key1")
+	lock.RLock("key2")
+	lock.RLock("key2")
+	assert.Equal(t, 2, lock.Len())
+
+	// 获取失败的 TryLock 不会残留引用
+	assert.False(t, lock.TryLock("key1"))
+	assert.False(t, lock.TryRLock("key1"))
+	assert.Equal(t, 2, lock.Len())
+
+	lock.Unlock("key1")
+	assert.Equal(t, 1, lock.Len())
+	lock.RUnLock("key2")
+	assert.Equal(t, 1, lock.Len())
+	lock.RUnLock("key2")
+	assert.Equal(t, 0, lock.Len())
+
+	// 解锁不存在的 key 什么也不做
+	lock.Unlock("key3")
+	lock.RUnLock("key3")
+	assert.Equal(t, 0, lock.Len())
+
+	var zero MapKeyLock
+	assert.True(t, zero.TryLock("key1"))
+	zero.Unlock("key1")
+	assert.Equal(t, 0, zero.Len())
+}
+
+func TestMapKeyLock_Concurrent(t *testing.T) {
+	lock := NewMapKeyLock()
+	var wg sync.WaitGroup
+	counters := make([]int, 10)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				idx := (i + j) % len(counters)
+				key := strconv.Itoa(idx)
+				lock.Lock(key)
+				counters[idx]++
+				lock.Unlock(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+	total := 0
+	for _, c := range counters {
+		total += c
+	}
+	assert.Equal(t, 100*100, total)
+	assert.Equal(t, 0, lock.Len())
+}
+
+func TestMapKeyLock_DistinctKeysStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping stress test in short mode")
+	}
+	lock := NewMapKeyLock()
+	const goroutines, keysPerGoroutine = 8, 250000
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < keysPerGoroutine; j++ {
+				key := strconv.Itoa(i*keysPerGoroutine + j)
+				if j%2 == 0 {
+					lock.Lock(key)
+					lock.Unlock(key)
+				} else {
+					lock.RLock(key)
+					lock.RUnLock(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	assert.Equal(t, 0, lock.Len())
+	// 两百万个不同的 key 全部解锁后，堆内存不应随 key 的数量增长
+	assert.Less(t, int64(after.HeapAlloc)-int64(before.HeapAlloc), int64(8<<20))
+}
+
+func TestMapKeyLock_LockContext(t *testing.T) {
+	lock := NewMapKeyLock()
+	key1, key2 := "key1", "key2"
+
+	assert.NoError(t, lock.LockContext(context.Background(), key1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, lock.LockContext(ctx, key1))
+	assert.Equal(t, context.DeadlineExceeded, lock.RLockContext(ctx, key1))
+	// 放弃等待后不会残留引用
+	assert.Equal(t, 1, lock.Len())
+
+	assert.NoError(t, lock.RLockContext(ctx, key2))
+	assert.True(t, lock.TryRLockTimeout(key2, 10*time.Millisecond))
+	assert.False(t, lock.TryLockTimeout(key2, 10*time.Millisecond))
+
+	// 锁在超时前被释放
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		lock.Unlock(key1)
+	}()
+	assert.True(t, lock.TryLockTimeout(key1, time.Second))
+	lock.Unlock(key1)
+	lock.RUnLock(key2)
+	lock.RUnLock(key2)
+	assert.Equal(t, 0, lock.Len())
+}
+
+func TestMapKeyLock_LockContextCancel(t *testing.T) {
+	lock := NewMapKeyLock()
+	lock.Lock("key")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- lock.LockContext(ctx, "key")
+	}()
+	cancel()
+	assert.Equal(t, context.Canceled, <-errCh)
+
+	lock.Unlock("key")
+	assert.Equal(t, 0, lock.Len())
+}
+
+func TestMapKeyLock_SafeUnlock(t *testing.T) {
+	testCases := []struct {
+		name    string
+		before  func(lock *MapKeyLock)
+		unlock  func(lock *MapKeyLock) error
+		wantErr error
+	}{
+		{
+			name:   "解锁不存在的 key",
+			before: func(lock *MapKeyLock) {},
+			unlock: func(lock *MapKeyLock) error {
+				return lock.SafeUnlock("key")
+			},
+			wantErr: ErrUnlockOfUnlocked,
+		},
+		{
+			name:   "读解锁不存在的 key",
+			before: func(lock *MapKeyLock) {},
+			unlock: func(lock *MapKeyLock) error {
+				return lock.SafeRUnLock("key")
+			},
+			wantErr: ErrUnlockOfUnlocked,
+		},
+		{
+			name: "对持有读锁的 key 调用 SafeUnlock",
+			before: func(lock *MapKeyLock) {
+				lock.RLock("key")
+			},
+			unlock: func(lock *MapKeyLock) error {
+				return lock.SafeUnlock("key")
+			},
+			wantErr: ErrUnlockWrongMode,
+		},
+		{
+			name: "对持有写锁的 key 调用 SafeRUnLock",
+			before: func(lock *MapKeyLock) {
+				lock.Lock("key")
+			},
+			unlock: func(lock *MapKeyLock) error {
+				return lock.SafeRUnLock("key")
+			},
+			wantErr: ErrUnlockWrongMode,
+		},
+		{
+			name: "重复解锁",
+			before: func(lock *MapKeyLock) {
+				lock.Lock("key")
+				lock.Unlock("key")
+			},
+			unlock: func(lock *MapKeyLock) error {
+				return lock.SafeUnlock("key")
+			},
+			wantErr: ErrUnlockOfUnlocked,
+		},
+		{
+			name: "正常解锁",
+			before: func(lock *MapKeyLock) {
+				lock.Lock("key")
+			},
+			unlock: func(lock *MapKeyLock) error {
+				return lock.SafeUnlock("key")
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			lock := NewMapKeyLock()
+			tt.before(lock)
+			err := tt.unlock(lock)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, "key: key")
+			}
+		})
+	}
+}
+
+func TestMapKeyLock_Debug(t *testing.T) {
+	lock := NewMapKeyLock()
+	assert.NotPanics(t, func() {
+		lock.Unlock("key")
+		lock.RUnLock("key")
+	})
+	lock.RLock("key")
+	assert.PanicsWithError(t, "syncx: unlock with wrong mode, key: key", func() {
+		lock.Unlock("key")
+	})
+	lock.RUnLock("key")
+
+	debugLock := NewMapKeyLock(WithDebug())
+	assert.PanicsWithError(t, "syncx: unlock of unlocked key, key: key, holders: none, waiters: 0", func() {
+		debugLock.Unlock("key")
+	})
+	assert.PanicsWithError(t, "syncx: unlock of unlocked key, key: key, holders: none, waiters: 0", func() {
+		debugLock.RUnLock("key")
+	})
+	debugLock.Lock("key")
+	debugLock.Unlock("key")
+	assert.Panics(t, func() {
+		debugLock.Unlock("key")
+	})
+}
+
+func TestMapKeyLock_DebugHolders(t *testing.T) {
+	lock := NewMapKeyLock(WithDebug())
+
+	// 错误信息中包含持有模式、持有者数量和等待者数量
+	lock.RLock("key")
+	assert.True(t, lock.TryRLock("key"))
+	assert.EqualError(t, lock.SafeUnlock("key"), "syncx: unlock with wrong mode, key: key, holders: 2 readers, waiters: 0")
+	lock.RUnLock("key")
+	assert.EqualError(t, lock.SafeUnlock("key"), "syncx: unlock with wrong mode, key: key, holders: 1 reader, waiters: 0")
+	lock.RUnLock("key")
+
+	lock.Lock("key")
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- lock.RLockContext(ctx, "key")
+	}()
+	assert.Eventually(t, func() bool {
+		err := lock.SafeRUnLock("key")
+		return err != nil && err.Error() == "syncx: unlock with wrong mode, key: key, holders: 1 writer, waiters: 1"
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-errCh)
+	lock.Unlock("key")
+	assert.Equal(t, 0, lock.Len())
+
+	// 非调试模式下不记录持有者
+	assert.EqualError(t, NewMapKeyLock().SafeUnlock("key"), "syncx: unlock of unlocked key, key: key")
+}
+
+func TestMapKeyLock_Locked(t *testing.T) {
+	lock := NewMapKeyLock()
+	assert.False(t, lock.Locked("key"))
+
+	lock.Lock("key")
+	assert.True(t, lock.Locked("key"))
+	lock.Unlock("key")
+	assert.False(t, lock.Locked("key"))
+
+	lock.RLock("key")
+	assert.True(t, lock.Locked("key"))
+	lock.RUnLock("key")
+	assert.False(t, lock.Locked("key"))
+}
+
+func TestMapKeyLock_LockGuard(t *testing.T) {
+	lock := NewMapKeyLock(WithDebug())
+
+	unlock := lock.LockGuard("key")
+	assert.False(t, lock.TryRLock("key"))
+	unlock()
+	// 多次调用只会解锁一次
+	assert.NotPanics(t, unlock)
+	assert.False(t, lock.Locked("key"))
+
+	rUnlock := lock.RLockGuard("key")
+	assert.True(t, lock.TryRLock("key"))
+	assert.False(t, lock.TryLock("key"))
+	rUnlock()
+	rUnlock()
+	lock.RUnLock("key")
+	assert.Equal(t, 0, lock.Len())
+}
+
+func TestKeyLock_Generic(t *testing.T) {
+	type orderId int64
+	lock := NewKeyLock[orderId](WithDebug())
+	key1, key2 := orderId(1), orderId(2)
+
+	lock.Lock(key1)
+	assert.False(t, lock.TryLock(key1))
+	assert.True(t, lock.TryRLock(key2))
+	assert.True(t, lock.Locked(key1))
+	assert.Equal(t, 2, lock.Len())
+
+	lock.Unlock(key1)
+	lock.RUnLock(key2)
+	assert.Equal(t, 0, lock.Len())
+	assert.PanicsWithError(t, "syncx: unlock of unlocked key, key: 1, holders: none, waiters: 0", func() {
+		lock.Unlock(key1)
+	})
+
+	type compositeKey struct {
+		tenant string
+		id     int64
+	}
+	compositeLock := NewKeyLock[compositeKey]()
+	unlock := compositeLock.LockGuard(compositeKey{tenant: "a", id: 1})
+	assert.True(t, compositeLock.TryLock(compositeKey{tenant: "b", id: 1}))
+	assert.False(t, compositeLock.TryLock(compositeKey{tenant: "a", id: 1}))
+	compositeLock.Unlock(compositeKey{tenant: "b", id: 1})
+	unlock()
+	assert.Equal(t, 0, compositeLock.Len())
+}
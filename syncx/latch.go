@@ -0,0 +1,86 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// Latch 是一次性的倒计时门闩，计数减到 0 后所有等待者被唤醒，之后的 Wait 立即返回；与 sync.WaitGroup 不同，Wait 可以通过 ctx 放弃等待
+// Latch is a one-shot countdown latch, all waiters are released once the count reaches 0 and later calls to Wait return immediately.
+// Unlike sync.WaitGroup, Wait can be abandoned through ctx.
+type Latch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{}
+}
+
+// NewLatch 创建一个计数为 count 的 Latch，count 小于等于 0 时 Latch 已经打开
+// NewLatch creates a Latch with the given count, the Latch is already open if count <= 0.
+func NewLatch(count int) *Latch {
+	l := &Latch{
+		count: max(count, 0),
+		done:  make(chan struct{}),
+	}
+	if l.count == 0 {
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown 将计数减一，计数减到 0 时唤醒所有等待者，计数已经为 0 时不做任何事
+// CountDown decrements the count and releases all the waiters when it reaches 0, it does nothing if the count is already 0.
+func (l *Latch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count == 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Wait 等待计数减到 0，ctx 先结束时返回 ctx.Err()
+// Wait waits for the count to reach 0, it returns ctx.Err() if ctx is done first.
+func (l *Latch) Wait(ctx context.Context) error {
+	select {
+	case <-l.done:
+		return nil
+	default:
+	}
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done 返回一个在计数减到 0 时关闭的 channel
+// Done returns a channel that is closed when the count reaches 0.
+func (l *Latch) Done() <-chan struct{} {
+	return l.done
+}
+
+// Count 返回当前的计数
+// Count returns the current count.
+func (l *Latch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
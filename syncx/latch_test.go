@@ -0,0 +1,90 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatch(t *testing.T) {
+	l := NewLatch(3)
+	assert.Equal(t, 3, l.Count())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, l.Wait(context.Background()))
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx))
+		cancel()
+		l.CountDown()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, l.Count())
+	<-l.Done()
+
+	// 计数为 0 后 CountDown 不做任何事，Wait 立即返回
+	l.CountDown()
+	assert.Equal(t, 0, l.Count())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, l.Wait(ctx))
+}
+
+func TestNewLatch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		count     int
+		wantCount int
+		wantOpen  bool
+	}{
+		{
+			name:      "正数",
+			count:     2,
+			wantCount: 2,
+		},
+		{
+			name:     "0",
+			wantOpen: true,
+		},
+		{
+			name:     "负数",
+			count:    -1,
+			wantOpen: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLatch(tt.count)
+			assert.Equal(t, tt.wantCount, l.Count())
+			select {
+			case <-l.Done():
+				assert.True(t, tt.wantOpen)
+			default:
+				assert.False(t, tt.wantOpen)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	_ LeaseLocker      = &MemoryLeaseLocker{}
+	_ ContextKeyLocker = &LeaseKeyLock{}
+)
+
+var (
+	// ErrLeaseHeld 表示 key 的租约正被其他持有者持有
+	// ErrLeaseHeld is returned when the lease of the key is held by another holder.
+	ErrLeaseHeld = errors.New("syncx: lease is held by another holder")
+	// ErrLeaseLost 表示租约已过期或已被其他持有者获取，持有者不应再访问受保护的资源
+	// ErrLeaseLost is returned when the lease has expired or has been taken by another holder,
+	// the holder must stop accessing the protected resource.
+	ErrLeaseLost = errors.New("syncx: lease is lost")
+	// ErrInvalidTTL 表示租约的 TTL 不是正数，或对于 LeaseKeyLock 来说过小，导致重试和续约间隔为 0
+	// ErrInvalidTTL is returned when the TTL of a lease is not positive,
+	// or too small for a LeaseKeyLock to derive non-zero retry and renew intervals.
+	ErrInvalidTTL = errors.New("syncx: ttl must be positive")
+)
+
+// Lease 是一次成功加锁得到的租约
+// Lease is the lease obtained by a successful acquisition.
+type Lease struct {
+	// Key 被锁住的 key
+	Key string
+	// Token 是隔离令牌（fencing token），同一个 key 每次被获取时 Token 都会单调递增，
+	// 受保护的资源应拒绝携带比已见过的更小 Token 的写入，以防止租约过期的旧持有者继续写入
+	// Token is the fencing token, which increases monotonically each time the key is acquired.
+	// The protected resource should reject writes carrying a token smaller than one it has already seen.
+	Token uint64
+	// ExpiresAt 租约的过期时间
+	ExpiresAt time.Time
+}
+
+// LeaseLocker 是基于租约的锁，可由 Redis、etcd 等分布式存储实现，从而在多个副本之间互斥
+// LeaseLocker is a lease-based lock, which can be backed by distributed stores such as Redis or etcd to provide mutual exclusion across replicas.
+type LeaseLocker interface {
+	// Acquire 尝试获取 key 的租约，租约在 ttl 后过期；key 的租约正被其他持有者持有时返回 ErrLeaseHeld
+	// Acquire tries to acquire the lease of the key for ttl, ErrLeaseHeld is returned if it is held by another holder.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+	// Renew 将租约的过期时间延长为从现在起的 ttl，租约已丢失时返回 ErrLeaseLost
+	// Renew extends the lease to expire ttl from now, ErrLeaseLost is returned if the lease is lost.
+	Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error)
+	// Release 释放租约，只有 Token 匹配时才会释放，租约已丢失时返回 ErrLeaseLost
+	// Release releases the lease only if the token matches, ErrLeaseLost is returned if the lease is lost.
+	Release(ctx context.Context, lease Lease) error
+}
+
+// MemoryLeaseLocker 是 LeaseLocker 的内存实现，与分布式实现遵循相同的语义，可用于测试和单进程场景
+// 过期的租约在对应的 key 下一次被访问时才会被清理
+// MemoryLeaseLocker is the in-memory LeaseLocker, which follows the same semantics as distributed implementations
+// and is useful for tests and single-process usage. Expired leases are removed the next time their keys are accessed.
+type MemoryLeaseLocker struct {
+	mu     sync.Mutex
+	clock  Clock
+	token  uint64
+	leases map[string]Lease
+}
+
+// NewMemoryLeaseLocker 创建一个 MemoryLeaseLocker，clock 为 nil 时使用 SystemClock
+// NewMemoryLeaseLocker creates a MemoryLeaseLocker, SystemClock is used if clock is nil.
+func NewMemoryLeaseLocker(clock Clock) *MemoryLeaseLocker {
+	return &MemoryLeaseLocker{
+		clock:  orSystemClock(clock),
+		leases: make(map[string]Lease),
+	}
+}
+
+func (m *MemoryLeaseLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 {
+		return Lease{}, ErrInvalidTTL
+	}
+	if err := ctx.Err(); err != nil {
+		return Lease{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	if _, ok := m.current(key, now); ok {
+		return Lease{}, ErrLeaseHeld
+	}
+	m.token++
+	lease := Lease{
+		Key:       key,
+		Token:     m.token,
+		ExpiresAt: now.Add(ttl),
+	}
+	m.leases[key] = lease
+	return lease, nil
+}
+
+func (m *MemoryLeaseLocker) Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 {
+		return Lease{}, ErrInvalidTTL
+	}
+	if err := ctx.Err(); err != nil {
+		return Lease{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	current, ok := m.current(lease.Key, now)
+	if !ok || current.Token != lease.Token {
+		return Lease{}, ErrLeaseLost
+	}
+	current.ExpiresAt = now.Add(ttl)
+	m.leases[lease.Key] = current
+	return current, nil
+}
+
+func (m *MemoryLeaseLocker) Release(ctx context.Context, lease Lease) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.current(lease.Key, m.clock.Now())
+	if !ok || current.Token != lease.Token {
+		return ErrLeaseLost
+	}
+	delete(m.leases, lease.Key)
+	return nil
+}
+
+// current 返回 key 当前未过期的租约，并清理已过期的租约，调用方需持有 m.mu
+func (m *MemoryLeaseLocker) current(key string, now time.Time) (Lease, bool) {
+	lease, ok := m.leases[key]
+	if !ok {
+		return Lease{}, false
+	}
+	if !now.Before(lease.ExpiresAt) {
+		delete(m.leases, key)
+		return Lease{}, false
+	}
+	return lease, true
+}
+
+// LeaseKeyLockOption 是 LeaseKeyLock 的可选配置
+// LeaseKeyLockOption configures a LeaseKeyLock.
+type LeaseKeyLockOption func(l *LeaseKeyLock)
+
+// WithLeaseClock 设置 LeaseKeyLock 用于等待重试和续约的时钟，默认为 SystemClock
+// WithLeaseClock sets the clock used for retrying and renewing, SystemClock by default.
+func WithLeaseClock(clock Clock) LeaseKeyLockOption {
+	return func(l *LeaseKeyLock) {
+		l.clock = orSystemClock(clock)
+	}
+}
+
+// WithRetryInterval 设置获取租约失败后重试的间隔，d <= 0 时使用默认值，即 TTL 的十分之一
+// WithRetryInterval sets the interval between attempts to acquire the lease, one tenth of the TTL if d <= 0.
+func WithRetryInterval(d time.Duration) LeaseKeyLockOption {
+	return func(l *LeaseKeyLock) {
+		l.retryInterval = d
+	}
+}
+
+// WithRenewErrorHandler 设置续约失败时的回调，err 为 ErrLeaseLost 时表示租约已丢失，不会再续约
+// WithRenewErrorHandler sets the callback invoked when renewing fails, the lease is not renewed any more if err is ErrLeaseLost.
+func WithRenewErrorHandler(fn func(key string, err error)) LeaseKeyLockOption {
+	return func(l *LeaseKeyLock) {
+		l.onRenewError = fn
+	}
+}
+
+// LeaseKeyLock 将 LeaseLocker 适配为 KeyLocker，加锁时轮询获取租约，持有期间每隔 TTL 的三分之一自动续约
+// 同一个 LeaseKeyLock 上的同一个 key 不可重入，即使租约已丢失，在持有者调用 Unlock 之前其他调用者也无法获取该 key；
+// 等待本地持有者的调用者会在其解锁后立即被唤醒，只有 key 被其他副本持有时才需要轮询
+// LeaseKeyLock adapts a LeaseLocker to KeyLocker. It polls to acquire the lease and renews it every third of the TTL while it is held.
+// A key is not reentrant on the same LeaseKeyLock, and even if its lease is lost, no other caller can lock it until the holder unlocks it.
+// Callers waiting for a local holder are woken as soon as it unlocks, polling is only needed while the key is held by another replica.
+type LeaseKeyLock struct {
+	locker        LeaseLocker
+	ttl           time.Duration
+	clock         Clock
+	retryInterval time.Duration
+	onRenewError  func(key string, err error)
+
+	mu   sync.Mutex
+	held map[string]*heldLease
+}
+
+type heldLease struct {
+	// acquired 表示租约已获取且未在释放中，为 false 时该 key 只是被本地占用，由 LeaseKeyLock.mu 保护
+	acquired bool
+	// released 在该记录被移除后关闭，用于唤醒等待该 key 的本地调用者
+	released chan struct{}
+
+	mu    sync.Mutex
+	lease Lease
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewLeaseKeyLock 创建一个 LeaseKeyLock，ttl 为每次获取或续约的租约时长，ttl 小于 10ns 时返回 ErrInvalidTTL
+// NewLeaseKeyLock creates a LeaseKeyLock, ttl is the duration of each acquired or renewed lease.
+// ErrInvalidTTL is returned if ttl is less than 10ns, since the retry interval and renew interval would be zero.
+func NewLeaseKeyLock(locker LeaseLocker, ttl time.Duration, opts ...LeaseKeyLockOption) (*LeaseKeyLock, error) {
+	if ttl/10 <= 0 {
+		return nil, ErrInvalidTTL
+	}
+	l := &LeaseKeyLock{
+		locker:        locker,
+		ttl:           ttl,
+		clock:         SystemClock,
+		retryInterval: ttl / 10,
+		held:          make(map[string]*heldLease),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.retryInterval <= 0 {
+		l.retryInterval = ttl / 10
+	}
+	return l, nil
+}
+
+// Lock 获取 key 的锁，获取租约出错时会持续重试直到成功
+// Lock locks the key, it keeps retrying until the lease is acquired.
+func (l *LeaseKeyLock) Lock(key string) {
+	for l.LockContext(context.Background(), key) != nil {
+		<-l.clock.After(l.retryInterval)
+	}
+}
+
+// LockContext 获取 key 的锁，在 ctx 结束前仍未获取到锁时放弃等待并返回 ctx.Err()，获取租约出现 ErrLeaseHeld 以外的错误时直接返回该错误
+// LockContext locks the key, or gives up and returns ctx.Err() if ctx is done before the lock is acquired.
+// Errors other than ErrLeaseHeld returned by the LeaseLocker are returned directly.
+func (l *LeaseKeyLock) LockContext(ctx context.Context, key string) error {
+	for {
+		released, err := l.acquire(ctx, key)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrLeaseHeld) {
+			return err
+		}
+		// key 被本地持有时等待其解锁，被其他副本持有时轮询
+		var retry <-chan time.Time
+		if released == nil {
+			retry = l.clock.After(l.retryInterval)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		case <-retry:
+		}
+	}
+}
+
+func (l *LeaseKeyLock) TryLock(key string) bool {
+	_, err := l.acquire(context.Background(), key)
+	return err == nil
+}
+
+func (l *LeaseKeyLock) TryLockTimeout(key string, d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return l.LockContext(ctx, key) == nil
+}
+
+// Unlock 停止续约并释放 key 的租约，key 未被持有时什么也不做
+// Unlock stops renewing and releases the lease of the key, it does nothing if the key is not held.
+func (l *LeaseKeyLock) Unlock(key string) {
+	_ = l.SafeUnlock(key)
+}
+
+// SafeUnlock 停止续约并释放 key 的租约，key 未被持有时返回 ErrUnlockOfUnlocked
+// 租约已丢失时返回 ErrLeaseLost，此时只会释放本地占用，不会影响其他持有者获取到的租约
+// SafeUnlock stops renewing and releases the lease of the key. ErrUnlockOfUnlocked is returned if the key is not held.
+// ErrLeaseLost is returned if the lease has been lost, in which case the lease of any other holder is left untouched.
+func (l *LeaseKeyLock) SafeUnlock(key string) error {
+	l.mu.Lock()
+	h, ok := l.held[key]
+	if !ok || !h.acquired {
+		l.mu.Unlock()
+		return fmt.Errorf("%w, key: %s", ErrUnlockOfUnlocked, key)
+	}
+	h.acquired = false
+	l.mu.Unlock()
+
+	close(h.stop)
+	<-h.done
+	err := l.locker.Release(context.Background(), h.current())
+	// 释放租约后再移除记录，被唤醒的本地调用者不会因为租约尚未释放而转为轮询
+	l.mu.Lock()
+	l.remove(key, h)
+	l.mu.Unlock()
+	return err
+}
+
+// Lease 返回当前进程持有的 key 的租约，可用于获取隔离令牌
+// Lease returns the lease of the key held by this process, which carries the fencing token.
+func (l *LeaseKeyLock) Lease(key string) (Lease, bool) {
+	l.mu.Lock()
+	h, ok := l.held[key]
+	ok = ok && h.acquired
+	l.mu.Unlock()
+	if !ok {
+		return Lease{}, false
+	}
+	return h.current(), true
+}
+
+// acquire 先在本地占用 key 再获取租约，成功后启动续约
+// key 已被本地占用时返回 ErrLeaseHeld 和在本地占用解除后关闭的 channel，从而保证租约过期的旧持有者调用 Unlock 之前，其记录不会被覆盖
+func (l *LeaseKeyLock) acquire(ctx context.Context, key string) (<-chan struct{}, error) {
+	l.mu.Lock()
+	if h, ok := l.held[key]; ok {
+		l.mu.Unlock()
+		return h.released, ErrLeaseHeld
+	}
+	h := &heldLease{
+		released: make(chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	l.held[key] = h
+	l.mu.Unlock()
+
+	lease, err := l.locker.Acquire(ctx, key, l.ttl)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil {
+		l.remove(key, h)
+		return nil, err
+	}
+	h.lease = lease
+	h.acquired = true
+	go l.renew(key, h)
+	return nil, nil
+}
+
+// remove 移除 key 的记录并唤醒等待该 key 的本地调用者，调用方需持有 l.mu
+func (l *LeaseKeyLock) remove(key string, h *heldLease) {
+	delete(l.held, key)
+	close(h.released)
+}
+
+// renew 每隔 TTL 的三分之一续约一次，直到被停止或租约丢失
+func (l *LeaseKeyLock) renew(key string, h *heldLease) {
+	defer close(h.done)
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-l.clock.After(l.ttl / 3):
+		}
+		lease, err := l.locker.Renew(context.Background(), h.current(), l.ttl)
+		if err != nil {
+			if l.onRenewError != nil {
+				l.onRenewError(key, err)
+			}
+			if errors.Is(err, ErrLeaseLost) {
+				return
+			}
+			continue
+		}
+		h.mu.Lock()
+		h.lease = lease
+		h.mu.Unlock()
+	}
+}
+
+func (h *heldLease) current() Lease {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lease
+}
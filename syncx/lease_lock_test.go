@@ -0,0 +1,280 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLeaseLocker(t *testing.T) {
+	ctx := context.Background()
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	locker := NewMemoryLeaseLocker(clock)
+
+	_, err := locker.Acquire(ctx, "key", 0)
+	assert.Equal(t, ErrInvalidTTL, err)
+
+	lease1, err := locker.Acquire(ctx, "key", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "key", lease1.Key)
+	assert.Equal(t, clock.Now().Add(time.Second), lease1.ExpiresAt)
+
+	// 租约未过期时其他持有者无法获取
+	_, err = locker.Acquire(ctx, "key", time.Second)
+	assert.Equal(t, ErrLeaseHeld, err)
+
+	// 续约延长过期时间
+	clock.Advance(800 * time.Millisecond)
+	lease1, err = locker.Renew(ctx, lease1, time.Second)
+	require.NoError(t, err)
+	clock.Advance(800 * time.Millisecond)
+	_, err = locker.Acquire(ctx, "key", time.Second)
+	assert.Equal(t, ErrLeaseHeld, err)
+
+	// 租约过期后可以被其他持有者获取，且隔离令牌递增
+	clock.Advance(200 * time.Millisecond)
+	lease2, err := locker.Acquire(ctx, "key", time.Second)
+	require.NoError(t, err)
+	assert.Greater(t, lease2.Token, lease1.Token)
+
+	// 旧持有者无法续约或释放
+	_, err = locker.Renew(ctx, lease1, time.Second)
+	assert.Equal(t, ErrLeaseLost, err)
+	assert.Equal(t, ErrLeaseLost, locker.Release(ctx, lease1))
+
+	require.NoError(t, locker.Release(ctx, lease2))
+	assert.Equal(t, ErrLeaseLost, locker.Release(ctx, lease2))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = locker.Acquire(cancelled, "key", time.Second)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestLeaseKeyLock(t *testing.T) {
+	_, err := NewLeaseKeyLock(NewMemoryLeaseLocker(nil), 0)
+	assert.Equal(t, ErrInvalidTTL, err)
+	// TTL 过小时续约和重试间隔为 0
+	_, err = NewLeaseKeyLock(NewMemoryLeaseLocker(nil), 9*time.Nanosecond)
+	assert.Equal(t, ErrInvalidTTL, err)
+
+	locker := NewMemoryLeaseLocker(nil)
+	// 模拟两个副本
+	replica1, err := NewLeaseKeyLock(locker, 30*time.Millisecond)
+	require.NoError(t, err)
+	replica2, err := NewLeaseKeyLock(locker, 30*time.Millisecond)
+	require.NoError(t, err)
+
+	replica1.Lock("key")
+	lease, ok := replica1.Lease("key")
+	assert.True(t, ok)
+	assert.False(t, replica2.TryLock("key"))
+
+	// 自动续约使租约在超过 TTL 后仍然有效
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, replica2.TryLock("key"))
+	renewed, ok := replica1.Lease("key")
+	assert.True(t, ok)
+	assert.Equal(t, lease.Token, renewed.Token)
+	assert.True(t, renewed.ExpiresAt.After(lease.ExpiresAt))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, replica2.LockContext(ctx, "key"))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		replica1.Unlock("key")
+	}()
+	assert.True(t, replica2.TryLockTimeout("key", time.Second))
+	lease2, ok := replica2.Lease("key")
+	assert.True(t, ok)
+	assert.Greater(t, lease2.Token, lease.Token)
+
+	_, ok = replica1.Lease("key")
+	assert.False(t, ok)
+	assert.ErrorIs(t, replica1.SafeUnlock("key"), ErrUnlockOfUnlocked)
+	assert.NoError(t, replica2.SafeUnlock("key"))
+}
+
+func TestLeaseKeyLock_LeaseLost(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	locker := NewMemoryLeaseLocker(clock)
+
+	var (
+		mu      sync.Mutex
+		lostErr error
+	)
+	lock, err := NewLeaseKeyLock(locker, 3*time.Second, WithLeaseClock(clock), WithRenewErrorHandler(func(key string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lostErr = err
+	}))
+	require.NoError(t, err)
+	assert.True(t, lock.TryLock("key"))
+	lease, _ := lock.Lease("key")
+
+	// 模拟租约被后端强制释放后，续约失败并通知租约丢失
+	require.NoError(t, locker.Release(context.Background(), lease))
+	assert.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return errors.Is(lostErr, ErrLeaseLost)
+	}, time.Second, time.Millisecond)
+	assert.ErrorIs(t, lock.SafeUnlock("key"), ErrLeaseLost)
+}
+
+// unavailableRenewLocker 模拟续约时后端不可用
+type unavailableRenewLocker struct {
+	LeaseLocker
+}
+
+func (unavailableRenewLocker) Renew(context.Context, Lease, time.Duration) (Lease, error) {
+	return Lease{}, errors.New("unavailable")
+}
+
+func TestLeaseKeyLock_StaleHolder(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	locker := unavailableRenewLocker{LeaseLocker: NewMemoryLeaseLocker(clock)}
+
+	lock, err := NewLeaseKeyLock(locker, 3*time.Second, WithLeaseClock(clock))
+	require.NoError(t, err)
+	replica, err := NewLeaseKeyLock(locker, 3*time.Second, WithLeaseClock(clock))
+	require.NoError(t, err)
+
+	assert.True(t, lock.TryLock("key"))
+	stale, _ := lock.Lease("key")
+
+	// 续约持续失败导致租约过期
+	clock.Advance(3 * time.Second)
+
+	// 旧持有者解锁之前，同一个 LeaseKeyLock 上的其他调用者无法获取该 key
+	assert.False(t, lock.TryLock("key"))
+	// 其他副本可以获取已过期的租约
+	assert.True(t, replica.TryLock("key"))
+	current, ok := replica.Lease("key")
+	require.True(t, ok)
+	assert.Greater(t, current.Token, stale.Token)
+
+	// 旧持有者解锁时得到 ErrLeaseLost，且不会释放其他副本的租约
+	assert.ErrorIs(t, lock.SafeUnlock("key"), ErrLeaseLost)
+	assert.False(t, lock.TryLock("key"))
+	lease, ok := replica.Lease("key")
+	assert.True(t, ok)
+	assert.Equal(t, current.Token, lease.Token)
+
+	require.NoError(t, replica.SafeUnlock("key"))
+	assert.True(t, lock.TryLock("key"))
+	assert.NoError(t, lock.SafeUnlock("key"))
+	assert.ErrorIs(t, lock.SafeUnlock("key"), ErrUnlockOfUnlocked)
+}
+
+func TestLeaseKeyLock_LocalWaiter(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	locker := NewMemoryLeaseLocker(clock)
+	lock, err := NewLeaseKeyLock(locker, 30*time.Second, WithLeaseClock(clock))
+	require.NoError(t, err)
+
+	assert.True(t, lock.TryLock("key"))
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	acquired := make(chan error)
+	go func() {
+		acquired <- lock.LockContext(context.Background(), "key")
+	}()
+
+	// 等待本地持有者的调用者不会轮询，时钟上只有持有者的续约在等待
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, clock.Waiters())
+
+	// 本地持有者解锁后无需推进时钟即可获取到锁
+	require.NoError(t, lock.SafeUnlock("key"))
+	select {
+	case err = <-acquired:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("local waiter was not woken")
+	}
+	require.NoError(t, lock.SafeUnlock("key"))
+
+	// key 被其他副本持有时轮询获取租约
+	clock = NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	locker = NewMemoryLeaseLocker(clock)
+	lock, err = NewLeaseKeyLock(locker, 30*time.Second, WithLeaseClock(clock))
+	require.NoError(t, err)
+	replica, err := NewLeaseKeyLock(locker, 30*time.Second, WithLeaseClock(clock))
+	require.NoError(t, err)
+	assert.True(t, replica.TryLock("key"))
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	go func() {
+		acquired <- lock.LockContext(context.Background(), "key")
+	}()
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, replica.SafeUnlock("key"))
+	select {
+	case <-acquired:
+		t.Fatal("woken by another replica")
+	case <-time.After(10 * time.Millisecond):
+	}
+	for {
+		clock.Advance(3 * time.Second)
+		select {
+		case err = <-acquired:
+			require.NoError(t, err)
+			assert.NoError(t, lock.SafeUnlock("key"))
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestLeaseKeyLock_Concurrent(t *testing.T) {
+	lock, err := NewLeaseKeyLock(NewMemoryLeaseLocker(nil), time.Second, WithRetryInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	var (
+		wg      sync.WaitGroup
+		counter int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				lock.Lock("key")
+				counter++
+				lock.Unlock("key")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, counter)
+}
@@ -20,7 +20,10 @@ import (
 )
 
 // Once is similar to sync.Once and overrides the Do and doSlow methods with the error return value
+// If f returns an error or panics, Once is not marked as done and f will be called again on the next Do,
+// the panic is converted to a *PanicError
 // Once 类似 sync.Once，在其基础上重写 Do 和 doSlow 方法，新增 error 返回值
+// 如果 f 返回 error 或发生 panic，Once 不会被标记为已完成，下次调用 Do 时会再次执行 f，panic 会被转换为 *PanicError
 type Once struct {
 	done uint32
 	m    sync.Mutex
@@ -37,10 +40,70 @@ func (o *Once) doSlow(f func() error) (err error) {
 	o.m.Lock()
 	defer o.m.Unlock()
 	if atomic.LoadUint32(&o.done) == 0 {
-		err = f()
+		err = callSafely(f)
 		if err == nil {
 			atomic.StoreUint32(&o.done, 1)
 		}
 	}
 	return
 }
+
+// Done reports whether f has been called successfully
+// Done 判断 f 是否已经成功执行
+func (o *Once) Done() bool {
+	return atomic.LoadUint32(&o.done) == 1
+}
+
+// Reset resets Once so that the next Do calls f again, e.g. after a config reload
+// Reset 重置 Once，使下一次 Do 再次执行 f，例如在配置重新加载之后
+func (o *Once) Reset() {
+	o.m.Lock()
+	defer o.m.Unlock()
+	atomic.StoreUint32(&o.done, 0)
+}
+
+// OnceValue returns a function that invokes f only once until it succeeds and returns the value returned by f
+// If f returns an error or panics, the returned function returns the error and calls f again on the next call
+// OnceValue 返回一个函数，该函数在 f 成功之前会调用 f，成功之后只返回 f 的返回值而不再调用
+// 如果 f 返回 error 或发生 panic，返回的函数会返回该错误，并在下次调用时再次执行 f
+func OnceValue[T any](f func() (T, error)) func() (T, error) {
+	var (
+		once  Once
+		value T
+	)
+	return func() (T, error) {
+		err := once.Do(func() (err error) {
+			value, err = f()
+			return err
+		})
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return value, nil
+	}
+}
+
+// OnceValues is similar to OnceValue, but f returns two values
+// OnceValues 类似 OnceValue，但 f 返回两个值
+func OnceValues[T1, T2 any](f func() (T1, T2, error)) func() (T1, T2, error) {
+	var (
+		once Once
+		v1   T1
+		v2   T2
+	)
+	return func() (T1, T2, error) {
+		err := once.Do(func() (err error) {
+			v1, v2, err = f()
+			return err
+		})
+		if err != nil {
+			var (
+				zero1 T1
+				zero2 T2
+			)
+			return zero1, zero2, err
+		}
+		return v1, v2, nil
+	}
+}
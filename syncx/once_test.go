@@ -88,3 +88,134 @@ func TestOnce_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestOnce_DoPanic(t *testing.T) {
+	once := Once{}
+	calls := 0
+	err := once.Do(func() error {
+		calls++
+		panic("boom")
+	})
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.False(t, once.Done())
+
+	// panic 不会使 Once 的锁处于不可用状态，下一次调用会再次执行 f
+	assert.NoError(t, once.Do(func() error {
+		calls++
+		return nil
+	}))
+	assert.True(t, once.Done())
+	assert.Equal(t, 2, calls)
+
+	wantErr := errors.New("error")
+	err = (&Once{}).Do(func() error {
+		panic(wantErr)
+	})
+	assert.ErrorIs(t, err, wantErr)
+}
+
+func TestOnce_Reset(t *testing.T) {
+	once := Once{}
+	var mu sync.Mutex
+	calls := 0
+	f := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil
+	}
+	for round := 1; round <= 3; round++ {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, once.Do(f))
+			}()
+		}
+		wg.Wait()
+		assert.True(t, once.Done())
+		assert.Equal(t, round, calls)
+		once.Reset()
+		assert.False(t, once.Done())
+	}
+}
+
+func TestOnceValue(t *testing.T) {
+	testCases := []struct {
+		name      string
+		failTimes int
+
+		wantCalls int
+	}{
+		{
+			name:      "no error",
+			failTimes: 0,
+			wantCalls: 1,
+		},
+		{
+			name:      "retry on error",
+			failTimes: 2,
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			get := OnceValue(func() (map[string]string, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				if calls <= tt.failTimes {
+					return nil, errors.New("error")
+				}
+				return map[string]string{"k": "v"}, nil
+			})
+			for i := 0; i < tt.failTimes; i++ {
+				res, err := get()
+				assert.Error(t, err)
+				assert.Nil(t, res)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					res, err := get()
+					assert.NoError(t, err)
+					assert.Equal(t, map[string]string{"k": "v"}, res)
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestOnceValues(t *testing.T) {
+	calls := 0
+	get := OnceValues(func() (string, int, error) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return "v", calls, nil
+	})
+	s, n, err := get()
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "", s)
+	assert.Equal(t, 0, n)
+
+	for i := 0; i < 3; i++ {
+		s, n, err = get()
+		assert.NoError(t, err)
+		assert.Equal(t, "v", s)
+		assert.Equal(t, 2, n)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError 是由 panic 转换而来的错误，记录了 panic 的值和堆栈
+// PanicError is the error converted from a panic, which records the panic value and the stack trace.
+type PanicError struct {
+	// Value 是传给 panic 的值
+	// Value is the value passed to panic.
+	Value any
+	// Stack 是发生 panic 时的堆栈
+	// Stack is the stack trace when the panic occurred.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("syncx: recovered from panic: %v", e.Value)
+}
+
+// Unwrap 在 panic 的值是 error 时返回该 error
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// callSafely 调用 f，并将 f 中发生的 panic 转换为 *PanicError
+func callSafely(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallSafely(t *testing.T) {
+	wantErr := errors.New("error")
+	testCases := []struct {
+		name string
+		f    func() error
+
+		wantErr   error
+		wantPanic any
+	}{
+		{
+			name: "no error",
+			f: func() error {
+				return nil
+			},
+		},
+		{
+			name: "return error",
+			f: func() error {
+				return wantErr
+			},
+			wantErr: wantErr,
+		},
+		{
+			name: "panic with value",
+			f: func() error {
+				panic("boom")
+			},
+			wantPanic: "boom",
+		},
+		{
+			name: "panic with error",
+			f: func() error {
+				panic(wantErr)
+			},
+			wantErr:   wantErr,
+			wantPanic: wantErr,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := callSafely(tt.f)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			var panicErr *PanicError
+			if tt.wantPanic == nil {
+				assert.False(t, errors.As(err, &panicErr))
+				return
+			}
+			assert.ErrorAs(t, err, &panicErr)
+			assert.Equal(t, tt.wantPanic, panicErr.Value)
+			assert.Contains(t, string(panicErr.Stack), "panic_test.go")
+			assert.Contains(t, panicErr.Error(), "syncx: recovered from panic")
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// Pool 是类型安全的 sync.Pool，对象在放回时会被重置，避免遗漏重置导致的数据残留
+// Pool is a type-safe sync.Pool that resets objects when they are put back, so that no stale data is left behind.
+type Pool[T any] struct {
+	p     sync.Pool
+	reset func(T)
+}
+
+// NewPool 创建一个 Pool，newFn 在池中没有可用对象时创建新的对象，reset 在 Put 时重置对象，为 nil 时不重置
+// NewPool creates a Pool, newFn creates a new object when none is available and reset resets an object on Put.
+// Objects are not reset if reset is nil.
+func NewPool[T any](newFn func() T, reset func(T)) *Pool[T] {
+	return &Pool[T]{
+		p: sync.Pool{
+			New: func() any {
+				return newFn()
+			},
+		},
+		reset: reset,
+	}
+}
+
+// Get 从池中取出一个对象，没有可用对象时创建新的对象
+// Get takes an object from the pool, or creates a new one if none is available.
+func (p *Pool[T]) Get() T {
+	return p.p.Get().(T)
+}
+
+// Put 重置对象并将其放回池中
+// Put resets the object and puts it back into the pool.
+func (p *Pool[T]) Put(t T) {
+	if p.reset != nil {
+		p.reset(t)
+	}
+	p.p.Put(t)
+}
+
+// BytePool 是按容量分级的 []byte 池，容量超过上限的切片不会被放回池中，避免长期持有过大的内存
+// BytePool is a pool of []byte with size classes, slices whose capacity exceeds the limit are not pooled
+// so that huge buffers are not retained.
+type BytePool struct {
+	// classes 是各级的容量，升序排列
+	classes []int
+	pools   []sync.Pool
+	// headers 复用存放切片的指针，避免 Put 时分配内存
+	headers sync.Pool
+}
+
+// NewBytePool 创建一个 BytePool，各级容量从 minSize 开始逐级翻倍，直到 maxSize
+// minSize 小于 1 时按 1 处理，maxSize 小于 minSize 时按 minSize 处理
+// NewBytePool creates a BytePool whose class capacities start at minSize and double up to maxSize.
+// minSize less than 1 is treated as 1, and maxSize less than minSize is treated as minSize.
+func NewBytePool(minSize, maxSize int) *BytePool {
+	minSize = max(minSize, 1)
+	maxSize = max(maxSize, minSize)
+	var classes []int
+	for size := minSize; size < maxSize; size *= 2 {
+		classes = append(classes, size)
+		if size > maxSize/2 {
+			break
+		}
+	}
+	classes = append(classes, maxSize)
+	return &BytePool{
+		classes: classes,
+		pools:   make([]sync.Pool, len(classes)),
+		headers: sync.Pool{
+			New: func() any {
+				return new([]byte)
+			},
+		},
+	}
+}
+
+// Get 返回一个长度为 size 的切片，其内容是未定义的；size 超过最大容量时直接分配而不使用池
+// Get returns a slice of length size whose content is undefined. It allocates directly without the pool if size exceeds the max size.
+func (p *BytePool) Get(size int) []byte {
+	idx := sort.SearchInts(p.classes, size)
+	if idx == len(p.classes) {
+		return make([]byte, size)
+	}
+	if h, ok := p.pools[idx].Get().(*[]byte); ok {
+		b := *h
+		*h = nil
+		p.headers.Put(h)
+		return b[:size]
+	}
+	return make([]byte, size, p.classes[idx])
+}
+
+// Put 将切片放回对应容量的池中，容量小于最小容量或超过最大容量的切片会被丢弃；放回后不能再使用该切片
+// Put puts the slice back into the pool of its capacity, slices whose capacity is below the min size or above the max size are dropped.
+// The slice must not be used after Put.
+func (p *BytePool) Put(b []byte) {
+	c := cap(b)
+	if c < p.classes[0] || c > p.classes[len(p.classes)-1] {
+		return
+	}
+	// 放入容量不超过 cap(b) 的最大一级，保证 Get 取出的切片容量足够
+	idx := sort.SearchInts(p.classes, c+1) - 1
+	h := p.headers.Get().(*[]byte)
+	*h = b[:0]
+	p.pools[idx].Put(h)
+}
+
+// BufferPool 是 bytes.Buffer 的池，容量超过上限的 Buffer 不会被放回池中，避免长期持有过大的内存
+// BufferPool is a pool of bytes.Buffer, buffers whose capacity exceeds the limit are not pooled so that huge buffers are not retained.
+type BufferPool struct {
+	pool   *Pool[*bytes.Buffer]
+	maxCap int
+}
+
+// NewBufferPool 创建一个 BufferPool，容量超过 maxCap 的 Buffer 在 Put 时被丢弃
+// NewBufferPool creates a BufferPool, buffers whose capacity exceeds maxCap are dropped on Put.
+func NewBufferPool(maxCap int) *BufferPool {
+	return &BufferPool{
+		pool: NewPool(func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		}, (*bytes.Buffer).Reset),
+		maxCap: maxCap,
+	}
+}
+
+// Get 返回一个空的 Buffer
+// Get returns an empty Buffer.
+func (p *BufferPool) Get() *bytes.Buffer {
+	return p.pool.Get()
+}
+
+// Put 清空 Buffer 并将其放回池中，容量超过上限时丢弃；放回后不能再使用该 Buffer
+// Put resets the Buffer and puts it back into the pool, or drops it if its capacity exceeds the limit.
+// The Buffer must not be used after Put.
+func (p *BufferPool) Put(buf *bytes.Buffer) {
+	if buf.Cap() > p.maxCap {
+		return
+	}
+	p.pool.Put(buf)
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	created := 0
+	p := NewPool(func() *bytes.Buffer {
+		created++
+		return new(bytes.Buffer)
+	}, (*bytes.Buffer).Reset)
+
+	buf := p.Get()
+	assert.Equal(t, 1, created)
+	buf.WriteString("hello")
+	p.Put(buf)
+	// sync.Pool 不保证对象一定被复用，但取出的对象一定是重置过的
+	assert.Equal(t, 0, p.Get().Len())
+
+	p = NewPool(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, nil)
+	p.Put(bytes.NewBufferString("hello"))
+	assert.NotPanics(t, func() {
+		p.Get()
+	})
+}
+
+func TestNewBytePool(t *testing.T) {
+	testCases := []struct {
+		name    string
+		minSize int
+		maxSize int
+		want    []int
+	}{
+		{
+			name:    "最大容量是最小容量的整数倍",
+			minSize: 64,
+			maxSize: 512,
+			want:    []int{64, 128, 256, 512},
+		},
+		{
+			name:    "最大容量不是最小容量的整数倍",
+			minSize: 64,
+			maxSize: 1000,
+			want:    []int{64, 128, 256, 512, 1000},
+		},
+		{
+			name:    "最大容量小于最小容量",
+			minSize: 64,
+			maxSize: 10,
+			want:    []int{64},
+		},
+		{
+			name:    "最小容量小于 1",
+			minSize: 0,
+			maxSize: 4,
+			want:    []int{1, 2, 4},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewBytePool(tt.minSize, tt.maxSize)
+			assert.Equal(t, tt.want, p.classes)
+		})
+	}
+}
+
+func TestBytePool(t *testing.T) {
+	p := NewBytePool(64, 1000)
+	testCases := []struct {
+		name    string
+		size    int
+		wantCap int
+	}{
+		{
+			name:    "小于最小容量",
+			size:    10,
+			wantCap: 64,
+		},
+		{
+			name:    "等于某一级容量",
+			size:    128,
+			wantCap: 128,
+		},
+		{
+			name:    "介于两级之间",
+			size:    600,
+			wantCap: 1000,
+		},
+		{
+			name:    "超过最大容量",
+			size:    2000,
+			wantCap: 2000,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			b := p.Get(tt.size)
+			assert.Len(t, b, tt.size)
+			assert.GreaterOrEqual(t, cap(b), tt.wantCap)
+			p.Put(b)
+		})
+	}
+}
+
+func TestBytePool_Put(t *testing.T) {
+	p := NewBytePool(64, 1024)
+	// 放回的切片总能满足其所在级别的请求
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for size := 1; size <= 1024; size += 37 {
+				b := p.Get(size)
+				assert.Len(t, b, size)
+				p.Put(b)
+				// 容量不在任何一级的切片也可以放回
+				p.Put(make([]byte, 0, size+i))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.NotPanics(t, func() {
+		p.Put(nil)
+		p.Put(make([]byte, 4096))
+	})
+}
+
+func TestBufferPool(t *testing.T) {
+	p := NewBufferPool(1024)
+	buf := p.Get()
+	buf.WriteString("hello")
+	p.Put(buf)
+	assert.Equal(t, 0, p.Get().Len())
+
+	huge := bytes.NewBuffer(make([]byte, 0, 4096))
+	p.Put(huge)
+	for i := 0; i < 10; i++ {
+		assert.LessOrEqual(t, p.Get().Cap(), 1024)
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	b.Run("sync.Pool", func(b *testing.B) {
+		p := sync.Pool{
+			New: func() any {
+				return new(bytes.Buffer)
+			},
+		}
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get().(*bytes.Buffer)
+				buf.WriteString("hello")
+				buf.Reset()
+				p.Put(buf)
+			}
+		})
+	})
+	b.Run("Pool", func(b *testing.B) {
+		p := NewPool(func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		}, (*bytes.Buffer).Reset)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get()
+				buf.WriteString("hello")
+				p.Put(buf)
+			}
+		})
+	})
+}
+
+func BenchmarkBytePool(b *testing.B) {
+	sizes := []int{100, 1000, 10000}
+	b.Run("make", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				buf := make([]byte, sizes[i%len(sizes)])
+				buf[0] = 1
+				i++
+			}
+		})
+	})
+	b.Run("BytePool", func(b *testing.B) {
+		p := NewBytePool(64, 16*1024)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				buf := p.Get(sizes[i%len(sizes)])
+				buf[0] = 1
+				p.Put(buf)
+				i++
+			}
+		})
+	})
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+	p := NewBufferPool(64 * 1024)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := p.Get()
+			buf.WriteString("hello world")
+			p.Put(buf)
+		}
+	})
+}
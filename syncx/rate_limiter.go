@@ -0,0 +1,164 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	_ Limiter = &TokenBucket{}
+	_ Limiter = &SlidingWindow{}
+)
+
+// ErrLimitUnreachable 表示限流器的限制永远无法被满足，例如速率为 0 且令牌已经耗尽
+// ErrLimitUnreachable is returned when the limit can never be satisfied, e.g. the rate is 0 and the tokens are exhausted.
+var ErrLimitUnreachable = errors.New("syncx: rate limit can never be satisfied")
+
+// Limiter 是进程内的限流器
+// Limiter is an in-process rate limiter.
+type Limiter interface {
+	// Allow 报告当前是否允许一次请求，允许时会消耗一次配额
+	// Allow reports whether a request is allowed now, and consumes the quota if so.
+	Allow() bool
+	// Wait 阻塞直到允许一次请求或 ctx 结束
+	// Wait blocks until a request is allowed or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// KeyedLimiter 为每个 key 维护一个独立的限流器，空闲超过 idleTimeout 的限流器会被回收
+// KeyedLimiter manages an independent limiter per key, and evicts the limiters that have been idle for longer than idleTimeout.
+type KeyedLimiter[K comparable] struct {
+	newLimiter  func() Limiter
+	idleTimeout time.Duration
+	clock       Clock
+
+	mu        sync.Mutex
+	limiters  map[K]*keyedLimiterEntry
+	lastSweep time.Time
+}
+
+type keyedLimiterEntry struct {
+	limiter  Limiter
+	lastUsed time.Time
+	// waiters 是正在 Wait 的调用者数量，有调用者等待的限流器不会被回收
+	waiters int
+}
+
+// NewKeyedLimiter 创建一个 KeyedLimiter，newLimiter 用于为新的 key 创建限流器，clock 为 nil 时使用 SystemClock
+// idleTimeout 应不小于限流器恢复全部配额所需的时间，否则回收后重新创建的限流器可能放行超出限制的请求；idleTimeout <= 0 时从不回收
+// NewKeyedLimiter creates a KeyedLimiter, newLimiter creates the limiter for a new key and SystemClock is used if clock is nil.
+// idleTimeout should be no less than the time a limiter takes to regain its full quota,
+// otherwise a limiter recreated after eviction may allow more requests than the limit. Limiters are never evicted if idleTimeout <= 0.
+func NewKeyedLimiter[K comparable](newLimiter func() Limiter, idleTimeout time.Duration, clock Clock) *KeyedLimiter[K] {
+	clock = orSystemClock(clock)
+	return &KeyedLimiter[K]{
+		newLimiter:  newLimiter,
+		idleTimeout: idleTimeout,
+		clock:       clock,
+		limiters:    make(map[K]*keyedLimiterEntry),
+		lastSweep:   clock.Now(),
+	}
+}
+
+// Allow 报告 key 当前是否允许一次请求
+// Allow reports whether a request with the key is allowed now.
+func (l *KeyedLimiter[K]) Allow(key K) bool {
+	l.mu.Lock()
+	e := l.entry(key)
+	l.mu.Unlock()
+	return e.limiter.Allow()
+}
+
+// Wait 阻塞直到 key 允许一次请求或 ctx 结束
+// Wait blocks until a request with the key is allowed or ctx is done.
+func (l *KeyedLimiter[K]) Wait(ctx context.Context, key K) error {
+	l.mu.Lock()
+	e := l.entry(key)
+	e.waiters++
+	l.mu.Unlock()
+
+	err := e.limiter.Wait(ctx)
+
+	l.mu.Lock()
+	e.waiters--
+	e.lastUsed = l.clock.Now()
+	l.mu.Unlock()
+	return err
+}
+
+// EvictIdle 立即回收所有空闲超过 idleTimeout 的限流器，并返回回收的数量
+// 过期的限流器在访问 KeyedLimiter 时也会被周期性地回收，通常无需手动调用
+// EvictIdle evicts all the limiters that have been idle for longer than idleTimeout right away and returns the number evicted.
+// Idle limiters are also evicted periodically while the KeyedLimiter is used, so calling it is usually unnecessary.
+func (l *KeyedLimiter[K]) EvictIdle() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.evict(l.clock.Now())
+}
+
+// Len 返回当前维护的限流器数量
+// Len returns the number of limiters currently managed.
+func (l *KeyedLimiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.limiters)
+}
+
+// entry 返回 key 对应的限流器，不存在时创建，调用方需持有 l.mu
+func (l *KeyedLimiter[K]) entry(key K) *keyedLimiterEntry {
+	now := l.clock.Now()
+	if now.Sub(l.lastSweep) >= l.idleTimeout {
+		l.evict(now)
+	}
+	e, ok := l.limiters[key]
+	if !ok {
+		e = &keyedLimiterEntry{
+			limiter: l.newLimiter(),
+		}
+		l.limiters[key] = e
+	}
+	e.lastUsed = now
+	return e
+}
+
+// evict 回收空闲的限流器，调用方需持有 l.mu
+func (l *KeyedLimiter[K]) evict(now time.Time) int {
+	l.lastSweep = now
+	if l.idleTimeout <= 0 {
+		return 0
+	}
+	evicted := 0
+	for key, e := range l.limiters {
+		if e.waiters == 0 && now.Sub(e.lastUsed) >= l.idleTimeout {
+			delete(l.limiters, key)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// waitUntil 等待 d 时间或 ctx 结束
+func waitUntil(ctx context.Context, clock Clock, d time.Duration) error {
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
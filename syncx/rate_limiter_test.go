@@ -0,0 +1,94 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := NewKeyedLimiter[string](func() Limiter {
+		return NewSlidingWindow(1, time.Second, clock)
+	}, time.Minute, clock)
+
+	// 每个 key 的限流器相互独立
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+	assert.True(t, l.Allow("b"))
+	assert.Equal(t, 2, l.Len())
+
+	clock.Advance(30 * time.Second)
+	assert.True(t, l.Allow("a"))
+
+	// b 空闲超过 idleTimeout，在访问时被回收
+	clock.Advance(30 * time.Second)
+	assert.True(t, l.Allow("c"))
+	assert.Equal(t, 2, l.Len())
+	assert.Equal(t, 0, l.EvictIdle())
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, 2, l.EvictIdle())
+	assert.Equal(t, 0, l.Len())
+}
+
+func TestKeyedLimiter_NoEviction(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := NewKeyedLimiter[string](func() Limiter {
+		return NewTokenBucket(1, 1, clock)
+	}, 0, clock)
+
+	// idleTimeout <= 0 时限流器不会被回收，限流依然生效
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+	assert.True(t, l.Allow("b"))
+	assert.Equal(t, 2, l.Len())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, 0, l.EvictIdle())
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+	assert.Equal(t, 2, l.Len())
+}
+
+func TestKeyedLimiter_Wait(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := NewKeyedLimiter[int](func() Limiter {
+		return NewTokenBucket(1, 1, clock)
+	}, time.Second, clock)
+	require.NoError(t, l.Wait(context.Background(), 1))
+
+	done := make(chan error)
+	go func() {
+		done <- l.Wait(context.Background(), 1)
+	}()
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+
+	// 正在等待的限流器不会被回收
+	clock.Advance(time.Second)
+	assert.Equal(t, 0, l.EvictIdle())
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, l.Len())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, 1, l.EvictIdle())
+}
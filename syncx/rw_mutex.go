@@ -0,0 +1,184 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// rwMutex 是支持 context 的读写锁，零值可直接使用
+// 与 sync.RWMutex 相同，等待中的写锁会阻止新的读锁，避免写锁饥饿，因此不能递归地获取读锁
+type rwMutex struct {
+	mu             sync.Mutex
+	readers        int
+	writer         bool
+	waitingWriters int
+	// changed 在锁的状态发生变化时被关闭，用于唤醒所有等待者
+	changed chan struct{}
+}
+
+func (m *rwMutex) Lock() {
+	_ = m.LockContext(context.Background())
+}
+
+func (m *rwMutex) RLock() {
+	_ = m.RLockContext(context.Background())
+}
+
+// LockContext 获取写锁，在 ctx 结束前仍未获取到锁时放弃等待并返回 ctx.Err()
+func (m *rwMutex) LockContext(ctx context.Context) error {
+	m.mu.Lock()
+	if m.canLock() {
+		m.writer = true
+		m.mu.Unlock()
+		return nil
+	}
+	m.waitingWriters++
+	for {
+		ch := m.waitChan()
+		m.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			m.mu.Lock()
+			m.waitingWriters--
+			// 放弃等待的写锁可能正阻止着读锁，需要唤醒它们
+			m.broadcast()
+			m.mu.Unlock()
+			return ctx.Err()
+		}
+		m.mu.Lock()
+		if m.canLock() {
+			m.waitingWriters--
+			m.writer = true
+			m.mu.Unlock()
+			return nil
+		}
+	}
+}
+
+// RLockContext 获取读锁，在 ctx 结束前仍未获取到锁时放弃等待并返回 ctx.Err()
+func (m *rwMutex) RLockContext(ctx context.Context) error {
+	m.mu.Lock()
+	for !m.canRLock() {
+		ch := m.waitChan()
+		m.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		m.mu.Lock()
+	}
+	m.readers++
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *rwMutex) TryLock() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.canLock() {
+		return false
+	}
+	m.writer = true
+	return true
+}
+
+func (m *rwMutex) TryRLock() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.canRLock() {
+		return false
+	}
+	m.readers++
+	return true
+}
+
+func (m *rwMutex) Unlock() {
+	if err := m.unlock(); err != nil {
+		panic(err)
+	}
+}
+
+func (m *rwMutex) RUnlock() {
+	if err := m.rUnlock(); err != nil {
+		panic(err)
+	}
+}
+
+// unlock 释放写锁，未持有写锁时返回 ErrUnlockOfUnlocked 或 ErrUnlockWrongMode
+func (m *rwMutex) unlock() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.writer {
+		if m.readers > 0 {
+			return ErrUnlockWrongMode
+		}
+		return ErrUnlockOfUnlocked
+	}
+	m.writer = false
+	m.broadcast()
+	return nil
+}
+
+// rUnlock 释放读锁，未持有读锁时返回 ErrUnlockOfUnlocked 或 ErrUnlockWrongMode
+func (m *rwMutex) rUnlock() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.readers == 0 {
+		if m.writer {
+			return ErrUnlockWrongMode
+		}
+		return ErrUnlockOfUnlocked
+	}
+	m.readers--
+	if m.readers == 0 {
+		m.broadcast()
+	}
+	return nil
+}
+
+// locked 判断是否被持有写锁或读锁
+func (m *rwMutex) locked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.writer || m.readers > 0
+}
+
+func (m *rwMutex) canLock() bool {
+	return !m.writer && m.readers == 0
+}
+
+func (m *rwMutex) canRLock() bool {
+	return !m.writer && m.waitingWriters == 0
+}
+
+// waitChan 返回用于等待状态变化的 channel，调用方需持有 m.mu
+func (m *rwMutex) waitChan() chan struct{} {
+	if m.changed == nil {
+		m.changed = make(chan struct{})
+	}
+	return m.changed
+}
+
+// broadcast 唤醒所有等待者，调用方需持有 m.mu
+func (m *rwMutex) broadcast() {
+	if m.changed != nil {
+		close(m.changed)
+		m.changed = nil
+	}
+}
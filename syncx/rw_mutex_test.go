@@ -0,0 +1,153 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRWMutex_TryLock(t *testing.T) {
+	var mu rwMutex
+	assert.True(t, mu.TryLock())
+	assert.False(t, mu.TryLock())
+	assert.False(t, mu.TryRLock())
+	mu.Unlock()
+
+	assert.True(t, mu.TryRLock())
+	assert.True(t, mu.TryRLock())
+	assert.False(t, mu.TryLock())
+	mu.RUnlock()
+	mu.RUnlock()
+	assert.True(t, mu.TryLock())
+	mu.Unlock()
+}
+
+func TestRWMutex_UnlockOfUnlocked(t *testing.T) {
+	var mu rwMutex
+	assert.Panics(t, mu.Unlock)
+	assert.Panics(t, mu.RUnlock)
+}
+
+func TestRWMutex_LockContext(t *testing.T) {
+	testCases := []struct {
+		name string
+		// hold 先持有锁
+		hold    func(mu *rwMutex)
+		lock    func(mu *rwMutex, ctx context.Context) error
+		wantErr error
+	}{
+		{
+			name:    "未被持有时获取写锁",
+			hold:    func(mu *rwMutex) {},
+			lock:    (*rwMutex).LockContext,
+			wantErr: nil,
+		},
+		{
+			name:    "写锁被持有时获取写锁超时",
+			hold:    (*rwMutex).Lock,
+			lock:    (*rwMutex).LockContext,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "读锁被持有时获取写锁超时",
+			hold:    (*rwMutex).RLock,
+			lock:    (*rwMutex).LockContext,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "读锁被持有时获取读锁",
+			hold:    (*rwMutex).RLock,
+			lock:    (*rwMutex).RLockContext,
+			wantErr: nil,
+		},
+		{
+			name:    "写锁被持有时获取读锁超时",
+			hold:    (*rwMutex).Lock,
+			lock:    (*rwMutex).RLockContext,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu rwMutex
+			tt.hold(&mu)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			assert.Equal(t, tt.wantErr, tt.lock(&mu, ctx))
+		})
+	}
+}
+
+func TestRWMutex_AbandonedWriterUnblocksReaders(t *testing.T) {
+	var mu rwMutex
+	mu.RLock()
+
+	// 等待中的写锁会阻止新的读锁
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- mu.LockContext(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		return !mu.TryRLock()
+	}, time.Second, time.Millisecond)
+
+	readCh := make(chan error)
+	go func() {
+		readCh <- mu.RLockContext(context.Background())
+	}()
+
+	// 写锁放弃等待后，被阻塞的读锁可以继续获取
+	cancel()
+	assert.Equal(t, context.Canceled, <-errCh)
+	assert.NoError(t, <-readCh)
+	mu.RUnlock()
+	mu.RUnlock()
+	assert.True(t, mu.TryLock())
+}
+
+func TestRWMutex_Concurrent(t *testing.T) {
+	var (
+		mu      rwMutex
+		wg      sync.WaitGroup
+		counter int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mu.Lock()
+				counter++
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mu.RLock()
+				_ = counter
+				mu.RUnlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 50*100, counter)
+}
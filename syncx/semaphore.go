@@ -0,0 +1,130 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrWeightExceedsSize 表示请求的权重超过了信号量的容量，永远无法被满足
+// ErrWeightExceedsSize is returned when the requested weight exceeds the size of the semaphore and can never be satisfied.
+var ErrWeightExceedsSize = errors.New("syncx: weight exceeds semaphore size")
+
+// Semaphore 是带权重的信号量，等待者按先进先出的顺序获取，避免大权重的请求被饿死
+// Semaphore is a weighted semaphore, waiters are served in FIFO order so that large requests are not starved.
+type Semaphore struct {
+	size    int64
+	mu      sync.Mutex
+	cur     int64
+	waiters list.List
+}
+
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore 创建一个容量为 size 的信号量
+// NewSemaphore creates a semaphore with the given size.
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{
+		size: size,
+	}
+}
+
+// Acquire 获取权重为 n 的资源，在 ctx 结束前仍未获取到时放弃等待并返回 ctx.Err()，n 超过容量时返回 ErrWeightExceedsSize
+// Acquire acquires n units of the semaphore, or gives up and returns ctx.Err() if ctx is done before that.
+// ErrWeightExceedsSize is returned if n exceeds the size.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	if n > s.size {
+		s.mu.Unlock()
+		return ErrWeightExceedsSize
+	}
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semaphoreWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-ready:
+		// 在 ctx 结束的同时获取成功，视为获取成功
+		return nil
+	default:
+	}
+	isFront := s.waiters.Front() == elem
+	s.waiters.Remove(elem)
+	// 排在队首的等待者放弃后，后面的等待者可能可以被满足
+	if isFront && s.size > s.cur {
+		s.notifyWaiters()
+	}
+	return ctx.Err()
+}
+
+// TryAcquire 尝试获取权重为 n 的资源而不阻塞，返回是否获取成功
+// TryAcquire acquires n units of the semaphore without blocking and reports whether it succeeded.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release 释放权重为 n 的资源，释放的权重超过已获取的权重时会 panic
+// Release releases n units of the semaphore, it panics if more than held is released.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("syncx: semaphore released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// notifyWaiters 按顺序唤醒可以被满足的等待者，调用方需持有 s.mu
+func (s *Semaphore) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(semaphoreWaiter)
+		if s.size-s.cur < w.n {
+			// 不跳过队首的等待者，以保证先进先出
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
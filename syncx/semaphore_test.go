@@ -0,0 +1,156 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemaphore_TryAcquire(t *testing.T) {
+	s := NewSemaphore(3)
+	assert.True(t, s.TryAcquire(2))
+	assert.False(t, s.TryAcquire(2))
+	assert.True(t, s.TryAcquire(1))
+	s.Release(3)
+	assert.True(t, s.TryAcquire(3))
+	s.Release(3)
+	assert.Panics(t, func() {
+		s.Release(1)
+	})
+}
+
+func TestSemaphore_Acquire(t *testing.T) {
+	testCases := []struct {
+		name    string
+		held    int64
+		n       int64
+		wantErr error
+	}{
+		{
+			name: "有足够的资源",
+			held: 1,
+			n:    2,
+		},
+		{
+			name:    "资源不足时超时",
+			held:    2,
+			n:       2,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "权重超过容量",
+			n:       4,
+			wantErr: ErrWeightExceedsSize,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSemaphore(3)
+			require.True(t, s.TryAcquire(tt.held))
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			assert.Equal(t, tt.wantErr, s.Acquire(ctx, tt.n))
+		})
+	}
+}
+
+func TestSemaphore_FIFO(t *testing.T) {
+	s := NewSemaphore(3)
+	require.NoError(t, s.Acquire(context.Background(), 3))
+
+	// 大权重的等待者排在前面时，后面的小权重等待者不能插队
+	big := make(chan error)
+	go func() {
+		big <- s.Acquire(context.Background(), 3)
+	}()
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+	assert.False(t, s.TryAcquire(1))
+
+	s.Release(3)
+	assert.NoError(t, <-big)
+	s.Release(3)
+}
+
+func TestSemaphore_CancelFrontWaiter(t *testing.T) {
+	s := NewSemaphore(3)
+	require.NoError(t, s.Acquire(context.Background(), 2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	big := make(chan error)
+	go func() {
+		big <- s.Acquire(ctx, 3)
+	}()
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+	small := make(chan error)
+	go func() {
+		small <- s.Acquire(context.Background(), 1)
+	}()
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.waiters.Len() == 2
+	}, time.Second, time.Millisecond)
+
+	// 队首的等待者放弃后，后面可以被满足的等待者被唤醒
+	cancel()
+	assert.Equal(t, context.Canceled, <-big)
+	assert.NoError(t, <-small)
+	s.Release(3)
+}
+
+func TestSemaphore_Concurrent(t *testing.T) {
+	const size = 5
+	s := NewSemaphore(size)
+	var (
+		wg      sync.WaitGroup
+		current atomic.Int64
+		peak    atomic.Int64
+	)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			require.NoError(t, s.Acquire(context.Background(), n))
+			defer s.Release(n)
+			cur := current.Add(n)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			current.Add(-n)
+		}(int64(i%3 + 1))
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, peak.Load(), int64(size))
+	assert.True(t, s.TryAcquire(size))
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// SingleFlight 合并相同 key 的并发调用，同一时间每个 key 只有一个调用在执行，其他调用者等待并共享其结果，零值可直接使用
+// SingleFlight deduplicates concurrent calls with the same key, only one call per key is in flight at a time
+// and the other callers wait for and share its result. The zero value is ready to use.
+type SingleFlight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flight[V]
+}
+
+// SingleFlightResult 是 DoChan 返回的结果
+// SingleFlightResult is the result delivered by DoChan.
+type SingleFlightResult[V any] struct {
+	Val V
+	Err error
+	// Shared 表示结果是否被多个调用者共享
+	// Shared reports whether the result was shared with other callers.
+	Shared bool
+}
+
+type flight[V any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	val    V
+	err    error
+	// dups 是加入该调用的其他调用者数量，waiters 是仍在等待的调用者数量，均由 SingleFlight.mu 保护
+	dups    int
+	waiters int
+}
+
+// Do 执行 fn 并返回其结果，如果相同 key 的调用正在执行，则等待并共享其结果
+// fn 接收的 ctx 不会因为某个调用者的 ctx 结束而取消，只有当所有调用者都放弃等待时才会被取消；调用者的 ctx 结束时，Do 立即返回 ctx.Err()
+// fn 中发生的 panic 会被转换为 *PanicError 返回给所有调用者
+// Do executes fn and returns its result, or waits for and shares the result of the in-flight call with the same key.
+// The ctx passed to fn is not canceled when a single caller's ctx is done, it is canceled only after all callers have given up.
+// Do returns ctx.Err() as soon as the caller's ctx is done. A panic in fn is returned to all callers as a *PanicError.
+func (g *SingleFlight[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (v V, err error, shared bool) {
+	f := g.join(ctx, key, fn)
+	return g.wait(ctx, key, f)
+}
+
+// DoChan 类似 Do，但返回一个接收结果的 channel，该 channel 只会接收一个结果
+// DoChan is like Do but returns a channel that receives the result exactly once.
+func (g *SingleFlight[K, V]) DoChan(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) <-chan SingleFlightResult[V] {
+	ch := make(chan SingleFlightResult[V], 1)
+	f := g.join(ctx, key, fn)
+	go func() {
+		v, err, shared := g.wait(ctx, key, f)
+		ch <- SingleFlightResult[V]{
+			Val:    v,
+			Err:    err,
+			Shared: shared,
+		}
+	}()
+	return ch
+}
+
+// Forget 使后续相同 key 的调用不再等待正在执行的调用，而是重新执行
+// Forget makes subsequent calls with the key execute again instead of waiting for the in-flight call.
+func (g *SingleFlight[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
+
+// join 加入 key 对应的调用，调用不存在时启动一个新的调用
+func (g *SingleFlight[K, V]) join(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) *flight[V] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flight[V])
+	}
+	if f, ok := g.calls[key]; ok {
+		f.dups++
+		f.waiters++
+		return f
+	}
+	flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	f := &flight[V]{
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		waiters: 1,
+	}
+	g.calls[key] = f
+	go g.run(flightCtx, key, f, fn)
+	return f
+}
+
+func (g *SingleFlight[K, V]) run(ctx context.Context, key K, f *flight[V], fn func(ctx context.Context) (V, error)) {
+	defer f.cancel()
+	f.err = callSafely(func() (err error) {
+		f.val, err = fn(ctx)
+		return err
+	})
+	g.mu.Lock()
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(f.done)
+}
+
+// wait 等待调用完成或 ctx 结束，最后一个放弃等待的调用者会取消该调用
+func (g *SingleFlight[K, V]) wait(ctx context.Context, key K, f *flight[V]) (v V, err error, shared bool) {
+	select {
+	case <-f.done:
+		return f.val, f.err, f.dups > 0
+	case <-ctx.Done():
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f.waiters--
+	if f.waiters == 0 {
+		f.cancel()
+		if g.calls[key] == f {
+			delete(g.calls, key)
+		}
+	}
+	return v, ctx.Err(), f.dups > 0
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlight_Do(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fn     func(ctx context.Context) (string, error)
+		want   string
+		wantFn func(t *testing.T, err error)
+	}{
+		{
+			name: "no error",
+			fn: func(ctx context.Context) (string, error) {
+				return "v", nil
+			},
+			want: "v",
+			wantFn: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "has error",
+			fn: func(ctx context.Context) (string, error) {
+				return "", errors.New("error")
+			},
+			wantFn: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "error")
+			},
+		},
+		{
+			name: "panic",
+			fn: func(ctx context.Context) (string, error) {
+				panic("boom")
+			},
+			wantFn: func(t *testing.T, err error) {
+				var panicErr *PanicError
+				assert.ErrorAs(t, err, &panicErr)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var g SingleFlight[string, string]
+			v, err, shared := g.Do(context.Background(), "key", tt.fn)
+			assert.Equal(t, tt.want, v)
+			assert.False(t, shared)
+			tt.wantFn(t, err)
+		})
+	}
+}
+
+func TestSingleFlight_DoConcurrent(t *testing.T) {
+	var (
+		g       SingleFlight[int, string]
+		calls   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	const keys, callers = 10, 100
+	fn := func(key int) func(ctx context.Context) (string, error) {
+		return func(ctx context.Context) (string, error) {
+			calls.Add(1)
+			<-release
+			return strconv.Itoa(key), nil
+		}
+	}
+	for key := 0; key < keys; key++ {
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(key int) {
+				defer wg.Done()
+				v, err, shared := g.Do(context.Background(), key, fn(key))
+				assert.NoError(t, err)
+				assert.Equal(t, strconv.Itoa(key), v)
+				assert.True(t, shared)
+			}(key)
+		}
+	}
+	// 等待所有调用者加入后再放行
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		total := 0
+		for _, f := range g.calls {
+			total += f.waiters
+		}
+		return total == keys*callers
+	}, 5*time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(keys), calls.Load())
+	assert.Empty(t, g.calls)
+}
+
+func TestSingleFlight_CancelOneWaiter(t *testing.T) {
+	var g SingleFlight[string, string]
+	release := make(chan struct{})
+	var fnCtx context.Context
+	fn := func(ctx context.Context) (string, error) {
+		fnCtx = ctx
+		<-release
+		return "v", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := g.DoChan(ctx, "key", fn)
+	second := g.DoChan(context.Background(), "key", fn)
+
+	// 第一个调用者放弃等待不会取消其他调用者共享的调用
+	cancel()
+	res := <-first
+	assert.Equal(t, context.Canceled, res.Err)
+
+	close(release)
+	res = <-second
+	assert.NoError(t, res.Err)
+	assert.Equal(t, "v", res.Val)
+	assert.True(t, res.Shared)
+	assert.Equal(t, context.Canceled, fnCtx.Err())
+}
+
+func TestSingleFlight_CancelAllWaiters(t *testing.T) {
+	var g SingleFlight[string, string]
+	started := make(chan struct{})
+	fnErr := make(chan error, 1)
+	fn := func(ctx context.Context) (string, error) {
+		close(started)
+		<-ctx.Done()
+		fnErr <- ctx.Err()
+		return "", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := g.DoChan(ctx, "key", fn)
+	<-started
+	cancel()
+	assert.Equal(t, context.Canceled, (<-ch).Err)
+	// 所有调用者都放弃等待后，调用被取消
+	assert.Equal(t, context.Canceled, <-fnErr)
+
+	// 被放弃的调用不会被后续调用共享
+	v, err, shared := g.Do(context.Background(), "key", func(ctx context.Context) (string, error) {
+		return "v", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "v", v)
+	assert.False(t, shared)
+}
+
+func TestSingleFlight_Forget(t *testing.T) {
+	var g SingleFlight[string, int]
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		n := calls.Add(1)
+		if n == 1 {
+			<-release
+		}
+		return int(n), nil
+	}
+
+	first := g.DoChan(context.Background(), "key", fn)
+	assert.Eventually(t, func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	g.Forget("key")
+	v, err, shared := g.Do(context.Background(), "key", fn)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+	assert.False(t, shared)
+
+	close(release)
+	assert.Equal(t, 1, (<-first).Val)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SlidingWindow 是滑动窗口日志限流器，任意长度为 window 的时间段内最多允许 limit 次请求
+// SlidingWindow is a sliding window log rate limiter, which allows at most limit requests in any period of length window.
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+	clock  Clock
+
+	mu sync.Mutex
+	// times 是记录请求时间的环形队列，head 指向最早的请求
+	times []time.Time
+	head  int
+	count int
+}
+
+// NewSlidingWindow 创建一个在 window 时间内最多允许 limit 次请求的滑动窗口限流器，clock 为 nil 时使用 SystemClock
+// NewSlidingWindow creates a sliding window limiter that allows at most limit requests per window, SystemClock is used if clock is nil.
+func NewSlidingWindow(limit int, window time.Duration, clock Clock) *SlidingWindow {
+	limit = max(limit, 0)
+	return &SlidingWindow{
+		limit:  limit,
+		window: window,
+		clock:  orSystemClock(clock),
+		times:  make([]time.Time, limit),
+	}
+}
+
+// Allow 报告当前窗口内是否还允许一次请求，允许时记录该请求
+// Allow reports whether a request is allowed in the current window and records it if so.
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.tryRecord(w.clock.Now())
+	return ok
+}
+
+// Wait 阻塞直到窗口内允许一次请求或 ctx 结束，limit 为 0 时返回 ErrLimitUnreachable
+// Wait blocks until a request is allowed in the window or ctx is done, ErrLimitUnreachable is returned if limit is 0.
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	if w.limit == 0 {
+		return ErrLimitUnreachable
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		w.mu.Lock()
+		delay, ok := w.tryRecord(w.clock.Now())
+		w.mu.Unlock()
+		if ok {
+			return nil
+		}
+		if err := waitUntil(ctx, w.clock, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Count 返回当前窗口内已经记录的请求数
+// Count returns the number of requests recorded in the current window.
+func (w *SlidingWindow) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expire(w.clock.Now())
+	return w.count
+}
+
+// tryRecord 在窗口未满时记录一次请求，否则返回距离最早的请求移出窗口还需等待的时间，调用方需持有 w.mu
+func (w *SlidingWindow) tryRecord(now time.Time) (time.Duration, bool) {
+	w.expire(now)
+	if w.count < w.limit {
+		w.times[(w.head+w.count)%w.limit] = now
+		w.count++
+		return 0, true
+	}
+	if w.limit == 0 {
+		return 0, false
+	}
+	return w.times[w.head].Add(w.window).Sub(now), false
+}
+
+// expire 移除已经移出窗口的请求，调用方需持有 w.mu
+func (w *SlidingWindow) expire(now time.Time) {
+	for w.count > 0 && !now.Before(w.times[w.head].Add(w.window)) {
+		w.head = (w.head + 1) % w.limit
+		w.count--
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlidingWindow_Allow(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewSlidingWindow(3, time.Second, clock)
+
+	assert.True(t, w.Allow())
+	clock.Advance(400 * time.Millisecond)
+	assert.True(t, w.Allow())
+	assert.True(t, w.Allow())
+	assert.False(t, w.Allow())
+	assert.Equal(t, 3, w.Count())
+
+	// 第一个请求移出窗口
+	clock.Advance(600 * time.Millisecond)
+	assert.Equal(t, 2, w.Count())
+	assert.True(t, w.Allow())
+	assert.False(t, w.Allow())
+
+	clock.Advance(400 * time.Millisecond)
+	assert.Equal(t, 1, w.Count())
+	assert.True(t, w.Allow())
+	assert.True(t, w.Allow())
+	assert.False(t, w.Allow())
+
+	assert.False(t, NewSlidingWindow(0, time.Second, clock).Allow())
+}
+
+func TestSlidingWindow_Wait(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewSlidingWindow(2, time.Second, clock)
+	require.NoError(t, w.Wait(context.Background()))
+	clock.Advance(300 * time.Millisecond)
+	require.NoError(t, w.Wait(context.Background()))
+
+	done := make(chan error)
+	go func() {
+		done <- w.Wait(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	// 等待到第一个请求移出窗口
+	clock.Advance(699 * time.Millisecond)
+	assert.Len(t, done, 0)
+	clock.Advance(time.Millisecond)
+	assert.NoError(t, <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, w.Wait(ctx))
+	assert.Equal(t, ErrLimitUnreachable, NewSlidingWindow(0, time.Second, clock).Wait(context.Background()))
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+var (
+	_ RWKeyLocker               = (*StripedLock[string])(nil)
+	_ GenericRWKeyLocker[int64] = (*StripedLock[int64])(nil)
+)
+
+// defaultStripes 是 StripedLock 默认的分段数量
+const defaultStripes = 64
+
+// StripedLock 将 key 哈希到固定数量的读写锁上，内存占用与 key 的数量无关
+// 不同的 key 可能被映射到同一把锁上，因此不能在持有一个 key 的锁时再获取另一个 key 的锁，需要同时锁住多个 key 时请使用 LockAll
+// StripedLock hashes keys onto a fixed number of read-write locks, so the memory usage does not depend on the number of keys.
+// Different keys may share a lock, use LockAll instead of nesting Lock calls when multiple keys need to be locked at once.
+// StripedLock 的零值不可用，必须通过 NewStripedLock 或 NewStringStripedLock 创建
+// The zero value of StripedLock is not usable, it must be created with NewStripedLock or NewStringStripedLock.
+type StripedLock[K any] struct {
+	stripes []sync.RWMutex
+	hasher  func(key K) uint64
+}
+
+// NewStripedLock 创建一个 StripedLock，stripes 为分段数量，小于等于 0 时使用默认值 64，hasher 用于计算 key 的哈希值，为 nil 时 panic
+// NewStripedLock creates a StripedLock with the given number of stripes (64 if stripes <= 0) and the hasher of keys.
+// It panics if hasher is nil.
+func NewStripedLock[K any](stripes int, hasher func(key K) uint64) *StripedLock[K] {
+	if hasher == nil {
+		panic("syncx: nil hasher for striped lock")
+	}
+	if stripes <= 0 {
+		stripes = defaultStripes
+	}
+	return &StripedLock[K]{
+		stripes: make([]sync.RWMutex, stripes),
+		hasher:  hasher,
+	}
+}
+
+// NewStringStripedLock 创建一个以 string 为 key 的 StripedLock
+// NewStringStripedLock creates a StripedLock for string keys.
+func NewStringStripedLock(stripes int) *StripedLock[string] {
+	return NewStripedLock[string](stripes, StringHasher)
+}
+
+func (l *StripedLock[K]) Lock(key K) {
+	l.stripe(key).Lock()
+}
+
+func (l *StripedLock[K]) Unlock(key K) {
+	l.stripe(key).Unlock()
+}
+
+func (l *StripedLock[K]) RLock(key K) {
+	l.stripe(key).RLock()
+}
+
+func (l *StripedLock[K]) RUnLock(key K) {
+	l.stripe(key).RUnlock()
+}
+
+func (l *StripedLock[K]) TryLock(key K) bool {
+	return l.stripe(key).TryLock()
+}
+
+func (l *StripedLock[K]) TryRLock(key K) bool {
+	return l.stripe(key).TryRLock()
+}
+
+// LockAll 按分段下标升序获取多个 key 的写锁，固定的加锁顺序避免了死锁，映射到同一分段的 key 只会加锁一次
+// LockAll locks multiple keys by acquiring their stripes in ascending order, which avoids deadlocks.
+// Keys mapped to the same stripe lock it only once.
+func (l *StripedLock[K]) LockAll(keys ...K) {
+	for _, idx := range l.indexes(keys) {
+		l.stripes[idx].Lock()
+	}
+}
+
+// UnlockAll 释放由 LockAll 获取的写锁
+// UnlockAll unlocks the keys locked by LockAll.
+func (l *StripedLock[K]) UnlockAll(keys ...K) {
+	indexes := l.indexes(keys)
+	for i := len(indexes) - 1; i >= 0; i-- {
+		l.stripes[indexes[i]].Unlock()
+	}
+}
+
+// RLockAll 按分段下标升序获取多个 key 的读锁
+// RLockAll read-locks multiple keys by acquiring their stripes in ascending order.
+func (l *StripedLock[K]) RLockAll(keys ...K) {
+	for _, idx := range l.indexes(keys) {
+		l.stripes[idx].RLock()
+	}
+}
+
+// RUnLockAll 释放由 RLockAll 获取的读锁
+// RUnLockAll unlocks the keys read-locked by RLockAll.
+func (l *StripedLock[K]) RUnLockAll(keys ...K) {
+	indexes := l.indexes(keys)
+	for i := len(indexes) - 1; i >= 0; i-- {
+		l.stripes[indexes[i]].RUnlock()
+	}
+}
+
+func (l *StripedLock[K]) stripe(key K) *sync.RWMutex {
+	return &l.stripes[l.index(key)]
+}
+
+func (l *StripedLock[K]) index(key K) int {
+	return int(l.hasher(key) % uint64(len(l.stripes)))
+}
+
+// indexes 返回 keys 对应的去重且升序排列的分段下标
+func (l *StripedLock[K]) indexes(keys []K) []int {
+	seen := make(map[int]struct{}, len(keys))
+	result := make([]int, 0, len(keys))
+	for _, key := range keys {
+		idx := l.index(key)
+		if _, ok := seen[idx]; ok {
+			continue
+		}
+		seen[idx] = struct{}{}
+		result = append(result, idx)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// StringHasher 使用 FNV-1a 算法计算字符串的哈希值
+// StringHasher hashes a string with FNV-1a.
+func StringHasher(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// integer 是所有整数类型的约束
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// IntegerHasher 使用 splitmix64 的混淆函数计算整数的哈希值，使连续的整数均匀地分布到各个分段
+// IntegerHasher hashes an integer with the splitmix64 finalizer, so that consecutive integers spread evenly across stripes.
+func IntegerHasher[K integer](key K) uint64 {
+	x := uint64(key)
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
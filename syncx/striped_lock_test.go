@@ -0,0 +1,120 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripedLock_Lock(t *testing.T) {
+	lock := NewStringStripedLock(0)
+	assert.Len(t, lock.stripes, defaultStripes)
+
+	lock.Lock("key1")
+	assert.False(t, lock.TryLock("key1"))
+	assert.False(t, lock.TryRLock("key1"))
+	lock.Unlock("key1")
+
+	lock.RLock("key1")
+	assert.True(t, lock.TryRLock("key1"))
+	assert.False(t, lock.TryLock("key1"))
+	lock.RUnLock("key1")
+	lock.RUnLock("key1")
+	assert.True(t, lock.TryLock("key1"))
+	lock.Unlock("key1")
+}
+
+func TestNewStripedLock_NilHasher(t *testing.T) {
+	assert.PanicsWithValue(t, "syncx: nil hasher for striped lock", func() {
+		NewStripedLock[int](4, nil)
+	})
+}
+
+func TestStripedLock_SharedStripe(t *testing.T) {
+	// 只有一个分段时所有 key 共享同一把锁
+	lock := NewStringStripedLock(1)
+	lock.Lock("key1")
+	assert.False(t, lock.TryLock("key2"))
+	lock.Unlock("key1")
+}
+
+func TestStripedLock_LockAll(t *testing.T) {
+	lock := NewStripedLock[int64](8, IntegerHasher[int64])
+	keys := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	lock.LockAll(keys...)
+	for _, key := range keys {
+		assert.False(t, lock.TryRLock(key))
+	}
+	lock.UnlockAll(keys...)
+
+	lock.RLockAll(keys...)
+	for _, key := range keys {
+		assert.False(t, lock.TryLock(key))
+	}
+	lock.RUnLockAll(keys...)
+	for _, key := range keys {
+		assert.True(t, lock.TryLock(key))
+		lock.Unlock(key)
+	}
+}
+
+func TestStripedLock_LockAllNoDeadlock(t *testing.T) {
+	lock := NewStripedLock[int](4, IntegerHasher[int])
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			// 以相反的顺序锁住相同的 key
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					lock.LockAll(1, 2, 3, 4)
+					lock.UnlockAll(1, 2, 3, 4)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					lock.LockAll(4, 3, 2, 1)
+					lock.UnlockAll(4, 3, 2, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("deadlock")
+	}
+}
+
+func TestIntegerHasher(t *testing.T) {
+	// 连续的整数应均匀地分布到各个分段
+	counts := make([]int, 16)
+	for i := 0; i < 16000; i++ {
+		counts[IntegerHasher(i)%16]++
+	}
+	for _, c := range counts {
+		assert.InDelta(t, 1000, c, 200)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket 是令牌桶限流器，令牌以固定速率生成，桶中最多存放 burst 个令牌
+// TokenBucket is a token bucket rate limiter, tokens are added at a fixed rate and the bucket holds at most burst tokens.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+	clock Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket 创建一个每秒生成 rate 个令牌、容量为 burst 的令牌桶，初始时桶是满的，clock 为 nil 时使用 SystemClock
+// NewTokenBucket creates a token bucket that adds rate tokens per second and holds at most burst tokens.
+// The bucket starts full, and SystemClock is used if clock is nil.
+func NewTokenBucket(rate float64, burst int, clock Clock) *TokenBucket {
+	clock = orSystemClock(clock)
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		clock:  clock,
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// Reservation 是 TokenBucket.Reserve 预定的令牌
+// Reservation is a token reserved by TokenBucket.Reserve.
+type Reservation struct {
+	tb        *TokenBucket
+	ok        bool
+	timeToAct time.Time
+	delay     time.Duration
+	once      sync.Once
+}
+
+// OK 报告预定是否成功，预定失败时不会消耗令牌
+// OK reports whether the reservation succeeded, a failed reservation consumes no token.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay 返回预定时距离令牌可用还需等待的时间
+// Delay returns how long to wait from the time of reservation until the token is available.
+func (r *Reservation) Delay() time.Duration {
+	return r.delay
+}
+
+// Cancel 在令牌可用之前取消预定，并将令牌归还给令牌桶；令牌已经可用时不做任何事
+// Cancel cancels the reservation before the token is available and returns the token to the bucket.
+// It does nothing if the token is already available.
+func (r *Reservation) Cancel() {
+	if !r.ok {
+		return
+	}
+	r.once.Do(func() {
+		r.tb.cancel(r.timeToAct)
+	})
+}
+
+// Allow 报告当前是否有可用的令牌，有则消耗一个令牌
+// Allow reports whether a token is available now and consumes it if so.
+func (tb *TokenBucket) Allow() bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.advance(tb.clock.Now())
+	if tb.tokens < 1 {
+		return false
+	}
+	tb.tokens--
+	return true
+}
+
+// Reserve 预定一个令牌并返回需要等待的时间，调用方应等待 Delay 之后再执行请求，不执行时应调用 Cancel 归还令牌
+// 令牌桶的容量小于 1 或速率不大于 0 且没有可用令牌时预定失败
+// Reserve reserves a token and reports how long to wait, the caller should act after Delay or call Cancel to return the token.
+// The reservation fails if burst is less than 1, or the rate is not positive and no token is available.
+func (tb *TokenBucket) Reserve() *Reservation {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	now := tb.clock.Now()
+	tb.advance(now)
+	r := &Reservation{tb: tb}
+	if tb.burst < 1 || (tb.rate <= 0 && tb.tokens < 1) {
+		return r
+	}
+	tb.tokens--
+	r.ok = true
+	if tb.tokens < 0 {
+		r.delay = time.Duration(math.Ceil(-tb.tokens / tb.rate * float64(time.Second)))
+	}
+	r.timeToAct = now.Add(r.delay)
+	return r
+}
+
+// Wait 阻塞直到获取到一个令牌或 ctx 结束，令牌永远无法获取时返回 ErrLimitUnreachable
+// Wait blocks until a token is acquired or ctx is done, ErrLimitUnreachable is returned if a token can never be acquired.
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r := tb.Reserve()
+	if !r.OK() {
+		return ErrLimitUnreachable
+	}
+	if r.Delay() <= 0 {
+		return nil
+	}
+	if err := waitUntil(ctx, tb.clock, r.Delay()); err != nil {
+		r.Cancel()
+		return err
+	}
+	return nil
+}
+
+// Tokens 返回当前可用的令牌数，有未到期的预定时可能为负数
+// Tokens returns the number of tokens available now, which may be negative while reservations are pending.
+func (tb *TokenBucket) Tokens() float64 {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.advance(tb.clock.Now())
+	return tb.tokens
+}
+
+// advance 根据经过的时间补充令牌，调用方需持有 tb.mu
+func (tb *TokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens = math.Min(tb.burst, tb.tokens+elapsed.Seconds()*tb.rate)
+		tb.last = now
+	}
+}
+
+func (tb *TokenBucket) cancel(timeToAct time.Time) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	now := tb.clock.Now()
+	if !timeToAct.After(now) {
+		return
+	}
+	tb.advance(now)
+	tb.tokens = math.Min(tb.burst, tb.tokens+1)
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	tb := NewTokenBucket(2, 3, clock)
+
+	// 初始时桶是满的
+	for i := 0; i < 3; i++ {
+		assert.True(t, tb.Allow())
+	}
+	assert.False(t, tb.Allow())
+
+	clock.Advance(500 * time.Millisecond)
+	assert.True(t, tb.Allow())
+	assert.False(t, tb.Allow())
+
+	// 令牌数不超过容量
+	clock.Advance(time.Hour)
+	assert.Equal(t, float64(3), tb.Tokens())
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rate      float64
+		burst     int
+		reserved  int
+		wantOK    bool
+		wantDelay time.Duration
+	}{
+		{
+			name:     "有可用的令牌",
+			rate:     10,
+			burst:    2,
+			reserved: 1,
+			wantOK:   true,
+		},
+		{
+			name:      "令牌耗尽时需要等待",
+			rate:      10,
+			burst:     2,
+			reserved:  4,
+			wantOK:    true,
+			wantDelay: 200 * time.Millisecond,
+		},
+		{
+			name:     "容量为 0",
+			rate:     10,
+			burst:    0,
+			reserved: 1,
+		},
+		{
+			name:     "速率为 0 且令牌耗尽",
+			rate:     0,
+			burst:    1,
+			reserved: 2,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			tb := NewTokenBucket(tt.rate, tt.burst, clock)
+			for i := 0; i < tt.reserved-1; i++ {
+				require.True(t, tb.Reserve().OK())
+			}
+			r := tb.Reserve()
+			assert.Equal(t, tt.wantOK, r.OK())
+			assert.Equal(t, tt.wantDelay, r.Delay())
+		})
+	}
+}
+
+func TestTokenBucket_ReserveCancel(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	tb := NewTokenBucket(1, 1, clock)
+
+	r := tb.Reserve()
+	assert.Equal(t, time.Duration(0), r.Delay())
+	r = tb.Reserve()
+	assert.Equal(t, time.Second, r.Delay())
+	assert.Equal(t, float64(-1), tb.Tokens())
+
+	// 令牌可用前取消会归还令牌，重复取消不会重复归还
+	r.Cancel()
+	r.Cancel()
+	assert.Equal(t, float64(0), tb.Tokens())
+
+	// 令牌已经可用时取消不做任何事
+	r = tb.Reserve()
+	clock.Advance(2 * time.Second)
+	r.Cancel()
+	assert.Equal(t, float64(1), tb.Tokens())
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	tb := NewTokenBucket(1, 1, clock)
+	require.NoError(t, tb.Wait(context.Background()))
+
+	done := make(chan error)
+	go func() {
+		done <- tb.Wait(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	assert.NoError(t, <-done)
+
+	// ctx 结束时放弃等待并归还令牌
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- tb.Wait(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Equal(t, float64(0), tb.Tokens())
+
+	assert.Equal(t, ErrLimitUnreachable, NewTokenBucket(0, 0, clock).Wait(context.Background()))
+}
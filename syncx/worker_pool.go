@@ -0,0 +1,194 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrPoolClosed 表示 WorkerPool 已经关闭，不再接收任务
+	// ErrPoolClosed is returned when submitting to a WorkerPool that has been shut down.
+	ErrPoolClosed = errors.New("syncx: worker pool is closed")
+	// ErrPoolFull 表示 WorkerPool 的任务队列已满
+	// ErrPoolFull is returned by TrySubmit when the task queue of the WorkerPool is full.
+	ErrPoolFull = errors.New("syncx: worker pool queue is full")
+)
+
+// WorkerPoolMetrics 是 WorkerPool 的运行指标
+// WorkerPoolMetrics are the metrics of a WorkerPool.
+type WorkerPoolMetrics struct {
+	// Queued 在队列中等待执行的任务数
+	Queued int64
+	// Running 正在执行的任务数
+	Running int64
+	// Completed 已经执行完成的任务数，包括发生 panic 的任务
+	Completed int64
+	// Panicked 发生 panic 的任务数
+	Panicked int64
+}
+
+// WorkerPoolOption 是 WorkerPool 的可选配置
+// WorkerPoolOption configures a WorkerPool.
+type WorkerPoolOption func(p *WorkerPool)
+
+// WithPanicHandler 设置任务发生 panic 时的回调
+// WithPanicHandler sets the callback invoked when a task panics.
+func WithPanicHandler(fn func(err *PanicError)) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.onPanic = fn
+	}
+}
+
+// WorkerPool 使用固定数量的 worker 执行任务，任务队列有界，单个任务中的 panic 会被恢复而不会影响 worker
+// WorkerPool runs tasks on a fixed number of workers with a bounded task queue, a panic in a task is recovered without affecting the worker.
+type WorkerPool struct {
+	tasks   chan func()
+	closing chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+	onPanic func(err *PanicError)
+
+	// mu 保证关闭 tasks 时没有正在发送的任务
+	mu     sync.RWMutex
+	closed bool
+
+	queued    atomic.Int64
+	running   atomic.Int64
+	completed atomic.Int64
+	panicked  atomic.Int64
+}
+
+// NewWorkerPool 创建一个 WorkerPool 并启动 workers 个 worker，queueSize 为任务队列的容量
+// workers 小于 1 时按 1 处理，queueSize 小于 0 时按 0 处理
+// NewWorkerPool creates a WorkerPool and starts the given number of workers, queueSize is the capacity of the task queue.
+// workers less than 1 is treated as 1, and queueSize less than 0 is treated as 0.
+func NewWorkerPool(workers, queueSize int, opts ...WorkerPoolOption) *WorkerPool {
+	p := &WorkerPool{
+		tasks:   make(chan func(), max(queueSize, 0)),
+		closing: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	workers = max(workers, 1)
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit 提交任务，队列已满时阻塞，直到任务入队、ctx 结束或 WorkerPool 关闭
+// Submit submits a task, it blocks while the queue is full until the task is queued, ctx is done or the pool is shut down.
+func (p *WorkerPool) Submit(ctx context.Context, task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.queued.Add(1)
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		return ctx.Err()
+	case <-p.closing:
+		p.queued.Add(-1)
+		return ErrPoolClosed
+	}
+}
+
+// TrySubmit 尝试提交任务而不阻塞，队列已满时返回 ErrPoolFull
+// TrySubmit submits a task without blocking, ErrPoolFull is returned if the queue is full.
+func (p *WorkerPool) TrySubmit(task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.queued.Add(1)
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+		p.queued.Add(-1)
+		return ErrPoolFull
+	}
+}
+
+// Shutdown 停止接收新任务，并等待已入队和正在执行的任务完成；在 ctx 结束前仍未完成时返回 ctx.Err()，剩余任务会在后台继续执行
+// Shutdown stops accepting new tasks and waits for the queued and running tasks to finish.
+// If ctx is done first, it returns ctx.Err() and the remaining tasks keep running in the background.
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		close(p.closing)
+		p.mu.Lock()
+		p.closed = true
+		close(p.tasks)
+		p.mu.Unlock()
+	})
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Metrics 返回 WorkerPool 当前的运行指标
+// Metrics returns the current metrics of the WorkerPool.
+func (p *WorkerPool) Metrics() WorkerPoolMetrics {
+	return WorkerPoolMetrics{
+		Queued:    p.queued.Load(),
+		Running:   p.running.Load(),
+		Completed: p.completed.Load(),
+		Panicked:  p.panicked.Load(),
+	}
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.queued.Add(-1)
+		p.running.Add(1)
+		p.run(task)
+		p.running.Add(-1)
+		p.completed.Add(1)
+	}
+}
+
+func (p *WorkerPool) run(task func()) {
+	err := callSafely(func() error {
+		task()
+		return nil
+	})
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		p.panicked.Add(1)
+		if p.onPanic != nil {
+			p.onPanic(panicErr)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		panicked []any
+	)
+	p := NewWorkerPool(4, 10, WithPanicHandler(func(err *PanicError) {
+		mu.Lock()
+		defer mu.Unlock()
+		panicked = append(panicked, err.Value)
+	}))
+
+	var counter atomic.Int64
+	for i := 0; i < 100; i++ {
+		i := i
+		require.NoError(t, p.Submit(context.Background(), func() {
+			if i%10 == 0 {
+				panic(i)
+			}
+			counter.Add(1)
+		}))
+	}
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Equal(t, int64(90), counter.Load())
+	assert.Len(t, panicked, 10)
+	assert.Equal(t, WorkerPoolMetrics{Completed: 100, Panicked: 10}, p.Metrics())
+
+	assert.Equal(t, ErrPoolClosed, p.Submit(context.Background(), func() {}))
+	assert.Equal(t, ErrPoolClosed, p.TrySubmit(func() {}))
+	// 重复关闭
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestWorkerPool_BoundedQueue(t *testing.T) {
+	p := NewWorkerPool(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), func() {
+		close(started)
+		<-release
+	}))
+	<-started
+	require.NoError(t, p.TrySubmit(func() {}))
+
+	// 队列已满
+	assert.Equal(t, ErrPoolFull, p.TrySubmit(func() {}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Submit(ctx, func() {}))
+	assert.Equal(t, WorkerPoolMetrics{Queued: 1, Running: 1}, p.Metrics())
+
+	// 等待任务完成超时
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Shutdown(ctx))
+
+	close(release)
+	assert.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, WorkerPoolMetrics{Completed: 2}, p.Metrics())
+}
+
+func TestWorkerPool_ShutdownUnblocksSubmit(t *testing.T) {
+	p := NewWorkerPool(0, -1)
+	release := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), func() {
+		<-release
+	}))
+
+	submitErr := make(chan error)
+	go func() {
+		submitErr <- p.Submit(context.Background(), func() {})
+	}()
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- p.Shutdown(context.Background())
+	}()
+	assert.Equal(t, ErrPoolClosed, <-submitErr)
+	close(release)
+	assert.NoError(t, <-shutdownErr)
+	assert.Equal(t, WorkerPoolMetrics{Completed: 1}, p.Metrics())
+}